@@ -8,6 +8,9 @@ import (
 	"os/signal"
 	"syscall"
 	"time"
+	// Embed the time zone database for the timezone export option, since
+	// the runtime image has none
+	_ "time/tzdata"
 
 	"rss-feed-to-csv/internal/config"
 	"rss-feed-to-csv/internal/handlers"
@@ -20,9 +23,16 @@ func main() {
 	
 	// Load configuration
 	cfg := config.Load()
+	if cfg.CapFetchRetryDeadline() {
+		log.Printf("[WARN] FETCH_RETRY_DEADLINE must leave time to write the response - Capped to: %v, WRITE_TIMEOUT: %v",
+			cfg.FetchRetryDeadline, cfg.WriteTimeout)
+	}
 	
 	// Create handler with config
-	handler := handlers.NewHandler(cfg)
+	handler, err := handlers.NewHandler(cfg)
+	if err != nil {
+		log.Fatalf("[ERROR] Failed to create handler: %v", err)
+	}
 	
 	// Create rate limiter
 	rateLimiter := middleware.NewRateLimiter(cfg.RateLimitPerMin)
@@ -31,6 +41,9 @@ func main() {
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", handler.HandleIndex)
 	mux.HandleFunc("/export", rateLimiter.Limit(handler.HandleExport))
+	mux.HandleFunc("/export/batch", rateLimiter.Limit(handler.HandleBatchExport))
+	mux.HandleFunc("/admin/breakers", handler.HandleBreakerStatus)
+	mux.HandleFunc("/admin/feeds", handler.HandleFeeds)
 	
 	// Create server with timeouts
 	srv := &http.Server{
@@ -2,7 +2,9 @@ package config
 
 import (
 	"os"
+	"path/filepath"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -13,19 +15,49 @@ type Config struct {
 	ReadTimeout     time.Duration
 	WriteTimeout    time.Duration
 	ShutdownTimeout time.Duration
-	
+
 	// RSS fetcher configuration
 	RSSFetchTimeout time.Duration
-	MaxRSSSize      int64 // TODO: Implement max RSS size check in fetcher
+	MaxRSSSize      int64 // Maximum feed body size in bytes; 0 disables the limit
 	UserAgent       string
-	
+
+	// Fetch retry configuration
+	FetchMaxAttempts    int           // Total attempts per fetch; 1 disables retries
+	FetchRetryBaseDelay time.Duration // Backoff before the first retry
+	FetchRetryMaxDelay  time.Duration // Upper bound on the backoff between attempts
+	FetchRetryDeadline  time.Duration // Total time allowed for a fetch and its retries
+
+	// Circuit breaker configuration
+	BreakerFailureThreshold int           // Consecutive failures that open a host's breaker; 0 disables it
+	BreakerOpenTimeout      time.Duration // How long a breaker stays open before a probe is allowed
+
+	// Batch export configuration
+	BatchMaxFeeds    int           // Most feeds accepted by one batch export
+	BatchConcurrency int           // Feeds of a batch fetched at the same time
+	BatchTimeout     time.Duration // Total time allowed for fetching the feeds of a batch
+
+	// Feed list configuration
+	FeedsFile     string // OPML file the saved feeds are kept in; empty keeps them in memory
+	FeedsMaxKnown int    // Recently exported feeds remembered for the OPML feed list
+
+	// Feed cache configuration
+	CacheBackend    string        // none, memory or disk
+	CacheDir        string        // Directory used by the disk backend
+	CacheMaxEntries int           // Maximum feeds kept by either backend
+	CacheMaxBytes   int64         // Maximum cached data kept by either backend; 0 disables the limit
+	CacheTTL        time.Duration // Freshness lifetime when the feed sends no max-age
+
 	// Security configuration
 	MaxURLLength    int
 	RateLimitPerMin int
-	
-	// CSV export configuration  
+	BlockedCIDRs    []string // Extra networks feeds may not be fetched from
+	AllowedCIDRs    []string // Networks exempt from the fetch address checks
+	AllowedHosts    []string // Host names exempt from the fetch address checks
+	AdminToken      string   // Bearer token for admin endpoints; empty disables them
+
+	// CSV export configuration
 	DefaultSanitize bool // TODO: Use as default when sanitize param not provided
-	
+
 	// Logging
 	LogLevel string // TODO: Implement structured logging
 }
@@ -37,19 +69,59 @@ func Load() *Config {
 		ReadTimeout:     getDuration("READ_TIMEOUT", 15*time.Second),
 		WriteTimeout:    getDuration("WRITE_TIMEOUT", 15*time.Second),
 		ShutdownTimeout: getDuration("SHUTDOWN_TIMEOUT", 30*time.Second),
-		
+
 		RSSFetchTimeout: getDuration("RSS_FETCH_TIMEOUT", 30*time.Second),
 		MaxRSSSize:      getInt64("MAX_RSS_SIZE", 10*1024*1024), // 10MB
 		UserAgent:       getEnv("USER_AGENT", "RSS-to-CSV-Exporter/1.0"),
-		
+
+		FetchMaxAttempts:    getInt("FETCH_MAX_ATTEMPTS", 3),
+		FetchRetryBaseDelay: getDuration("FETCH_RETRY_BASE_DELAY", 500*time.Millisecond),
+		FetchRetryMaxDelay:  getDuration("FETCH_RETRY_MAX_DELAY", 10*time.Second),
+		FetchRetryDeadline:  getDuration("FETCH_RETRY_DEADLINE", 10*time.Second),
+
+		BreakerFailureThreshold: getInt("BREAKER_FAILURE_THRESHOLD", 5),
+		BreakerOpenTimeout:      getDuration("BREAKER_OPEN_TIMEOUT", 30*time.Second),
+
+		BatchMaxFeeds:    getInt("BATCH_MAX_FEEDS", 50),
+		BatchConcurrency: getInt("BATCH_CONCURRENCY", 4),
+		BatchTimeout:     getDuration("BATCH_TIMEOUT", 2*time.Minute),
+
+		FeedsFile:     getEnv("FEEDS_FILE", ""),
+		FeedsMaxKnown: getInt("FEEDS_MAX_KNOWN", 500),
+
+		CacheBackend:    getEnv("CACHE_BACKEND", "memory"),
+		CacheDir:        getEnv("CACHE_DIR", filepath.Join(os.TempDir(), "rss-feed-cache")),
+		CacheMaxEntries: getInt("CACHE_MAX_ENTRIES", 100),
+		CacheMaxBytes:   getInt64("CACHE_MAX_BYTES", 64*1024*1024),
+		CacheTTL:        getDuration("CACHE_TTL", 5*time.Minute),
+
 		MaxURLLength:    getInt("MAX_URL_LENGTH", 2048),
 		RateLimitPerMin: getInt("RATE_LIMIT_PER_MIN", 60),
-		
+		BlockedCIDRs:    getList("BLOCKED_CIDRS"),
+		AllowedCIDRs:    getList("ALLOWED_CIDRS"),
+		AllowedHosts:    getList("ALLOWED_HOSTS"),
+		AdminToken:      getEnv("ADMIN_TOKEN", ""),
+
 		DefaultSanitize: getBool("DEFAULT_SANITIZE", false),
 		LogLevel:        getEnv("LOG_LEVEL", "INFO"),
 	}
 }
 
+// CapFetchRetryDeadline keeps a single fetch within two thirds of the write
+// timeout, so a fetch that succeeds late still leaves time to write the
+// export. It reports whether FetchRetryDeadline was lowered.
+func (c *Config) CapFetchRetryDeadline() bool {
+	if c.WriteTimeout <= 0 {
+		return false
+	}
+	limit := c.WriteTimeout * 2 / 3
+	if c.FetchRetryDeadline > 0 && c.FetchRetryDeadline <= limit {
+		return false
+	}
+	c.FetchRetryDeadline = limit
+	return true
+}
+
 // getEnv gets an environment variable with a fallback default
 func getEnv(key, defaultValue string) string {
 	if value := os.Getenv(key); value != "" {
@@ -96,4 +168,15 @@ func getBool(key string, defaultValue bool) bool {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
+
+// getList gets a comma-separated list from environment variable
+func getList(key string) []string {
+	var values []string
+	for _, value := range strings.Split(os.Getenv(key), ",") {
+		if value = strings.TrimSpace(value); value != "" {
+			values = append(values, value)
+		}
+	}
+	return values
+}
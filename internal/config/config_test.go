@@ -13,13 +13,18 @@ func TestLoad(t *testing.T) {
 		"PORT", "READ_TIMEOUT", "WRITE_TIMEOUT", "SHUTDOWN_TIMEOUT",
 		"RSS_FETCH_TIMEOUT", "MAX_RSS_SIZE", "USER_AGENT",
 		"MAX_URL_LENGTH", "RATE_LIMIT_PER_MIN", "DEFAULT_SANITIZE", "LOG_LEVEL",
+		"BLOCKED_CIDRS", "ALLOWED_CIDRS", "ALLOWED_HOSTS",
+		"CACHE_BACKEND", "CACHE_DIR", "CACHE_MAX_ENTRIES", "CACHE_MAX_BYTES", "CACHE_TTL",
+		"FETCH_MAX_ATTEMPTS", "FETCH_RETRY_BASE_DELAY", "FETCH_RETRY_MAX_DELAY", "FETCH_RETRY_DEADLINE",
+		"BREAKER_FAILURE_THRESHOLD", "BREAKER_OPEN_TIMEOUT", "ADMIN_TOKEN",
+		"BATCH_MAX_FEEDS", "BATCH_CONCURRENCY", "BATCH_TIMEOUT", "FEEDS_FILE", "FEEDS_MAX_KNOWN",
 	}
-	
+
 	for _, key := range envVars {
 		originalEnv[key] = os.Getenv(key)
 		os.Unsetenv(key)
 	}
-	
+
 	// Restore env vars after test
 	defer func() {
 		for key, value := range originalEnv {
@@ -31,7 +36,7 @@ func TestLoad(t *testing.T) {
 
 	t.Run("default values", func(t *testing.T) {
 		cfg := Load()
-		
+
 		if cfg.Port != ":8080" {
 			t.Errorf("Port = %s, want :8080", cfg.Port)
 		}
@@ -53,6 +58,27 @@ func TestLoad(t *testing.T) {
 		if cfg.DefaultSanitize != false {
 			t.Errorf("DefaultSanitize = %v, want false", cfg.DefaultSanitize)
 		}
+		if cfg.FetchMaxAttempts != 3 || cfg.FetchRetryBaseDelay != 500*time.Millisecond ||
+			cfg.FetchRetryMaxDelay != 10*time.Second || cfg.FetchRetryDeadline != 10*time.Second {
+			t.Errorf("retry = %d/%v/%v/%v, want 3/500ms/10s/10s",
+				cfg.FetchMaxAttempts, cfg.FetchRetryBaseDelay, cfg.FetchRetryMaxDelay, cfg.FetchRetryDeadline)
+		}
+		if cfg.BreakerFailureThreshold != 5 || cfg.BreakerOpenTimeout != 30*time.Second || cfg.AdminToken != "" {
+			t.Errorf("breaker = %d/%v, admin token %q, want 5/30s and no token",
+				cfg.BreakerFailureThreshold, cfg.BreakerOpenTimeout, cfg.AdminToken)
+		}
+		if cfg.CacheBackend != "memory" || cfg.CacheMaxEntries != 100 || cfg.CacheMaxBytes != 64*1024*1024 || cfg.CacheTTL != 5*time.Minute {
+			t.Errorf("cache = %s/%d/%d/%v, want memory/100/64MB/5m", cfg.CacheBackend, cfg.CacheMaxEntries, cfg.CacheMaxBytes, cfg.CacheTTL)
+		}
+		if cfg.BatchMaxFeeds != 50 || cfg.BatchConcurrency != 4 || cfg.BatchTimeout != 2*time.Minute {
+			t.Errorf("batch = %d/%d/%v, want 50/4/2m", cfg.BatchMaxFeeds, cfg.BatchConcurrency, cfg.BatchTimeout)
+		}
+		if cfg.FeedsFile != "" || cfg.FeedsMaxKnown != 500 {
+			t.Errorf("feeds = %q/%d, want no file and 500", cfg.FeedsFile, cfg.FeedsMaxKnown)
+		}
+		if len(cfg.BlockedCIDRs) != 0 || len(cfg.AllowedCIDRs) != 0 || len(cfg.AllowedHosts) != 0 {
+			t.Errorf("address lists = %v / %v / %v, want empty", cfg.BlockedCIDRs, cfg.AllowedCIDRs, cfg.AllowedHosts)
+		}
 	})
 
 	t.Run("custom values from env", func(t *testing.T) {
@@ -61,9 +87,20 @@ func TestLoad(t *testing.T) {
 		os.Setenv("MAX_RSS_SIZE", "5242880")
 		os.Setenv("DEFAULT_SANITIZE", "true")
 		os.Setenv("LOG_LEVEL", "DEBUG")
-		
+		os.Setenv("ALLOWED_CIDRS", "10.20.0.0/16, ,192.168.5.5")
+		os.Setenv("ALLOWED_HOSTS", "feeds.internal")
+		os.Setenv("CACHE_BACKEND", "disk")
+		os.Setenv("CACHE_DIR", "/var/cache/rss")
+		os.Setenv("CACHE_TTL", "1h")
+		os.Setenv("CACHE_MAX_BYTES", "1048576")
+		os.Setenv("FETCH_MAX_ATTEMPTS", "5")
+		os.Setenv("FETCH_RETRY_DEADLINE", "2m")
+		os.Setenv("BATCH_CONCURRENCY", "8")
+		os.Setenv("BATCH_TIMEOUT", "5m")
+		os.Setenv("FEEDS_FILE", "/var/lib/rss/feeds.opml")
+
 		cfg := Load()
-		
+
 		if cfg.Port != ":9090" {
 			t.Errorf("Port = %s, want :9090", cfg.Port)
 		}
@@ -79,15 +116,36 @@ func TestLoad(t *testing.T) {
 		if cfg.LogLevel != "DEBUG" {
 			t.Errorf("LogLevel = %s, want DEBUG", cfg.LogLevel)
 		}
+		if len(cfg.AllowedCIDRs) != 2 || cfg.AllowedCIDRs[0] != "10.20.0.0/16" || cfg.AllowedCIDRs[1] != "192.168.5.5" {
+			t.Errorf("AllowedCIDRs = %v, want [10.20.0.0/16 192.168.5.5]", cfg.AllowedCIDRs)
+		}
+		if cfg.FetchMaxAttempts != 5 || cfg.FetchRetryDeadline != 2*time.Minute {
+			t.Errorf("retry = %d/%v, want 5/2m", cfg.FetchMaxAttempts, cfg.FetchRetryDeadline)
+		}
+		if cfg.CacheBackend != "disk" || cfg.CacheDir != "/var/cache/rss" || cfg.CacheTTL != time.Hour {
+			t.Errorf("cache = %s/%s/%v, want disk//var/cache/rss/1h", cfg.CacheBackend, cfg.CacheDir, cfg.CacheTTL)
+		}
+		if cfg.CacheMaxBytes != 1048576 {
+			t.Errorf("CacheMaxBytes = %d, want 1048576", cfg.CacheMaxBytes)
+		}
+		if cfg.BatchConcurrency != 8 || cfg.BatchTimeout != 5*time.Minute {
+			t.Errorf("batch = %d/%v, want 8/5m", cfg.BatchConcurrency, cfg.BatchTimeout)
+		}
+		if cfg.FeedsFile != "/var/lib/rss/feeds.opml" {
+			t.Errorf("FeedsFile = %s, want /var/lib/rss/feeds.opml", cfg.FeedsFile)
+		}
+		if len(cfg.AllowedHosts) != 1 || cfg.AllowedHosts[0] != "feeds.internal" {
+			t.Errorf("AllowedHosts = %v, want [feeds.internal]", cfg.AllowedHosts)
+		}
 	})
 
 	t.Run("invalid env values use defaults", func(t *testing.T) {
 		os.Setenv("READ_TIMEOUT", "invalid")
 		os.Setenv("MAX_RSS_SIZE", "not-a-number")
 		os.Setenv("DEFAULT_SANITIZE", "not-a-bool")
-		
+
 		cfg := Load()
-		
+
 		// Should fall back to defaults
 		if cfg.ReadTimeout != 15*time.Second {
 			t.Errorf("ReadTimeout = %v, want 15s (default)", cfg.ReadTimeout)
@@ -99,4 +157,31 @@ func TestLoad(t *testing.T) {
 			t.Errorf("DefaultSanitize = %v, want false (default)", cfg.DefaultSanitize)
 		}
 	})
-}
\ No newline at end of file
+}
+
+func TestConfig_CapFetchRetryDeadline(t *testing.T) {
+	tests := []struct {
+		name         string
+		writeTimeout time.Duration
+		deadline     time.Duration
+		want         time.Duration
+		wantCapped   bool
+	}{
+		{name: "defaults", writeTimeout: 15 * time.Second, deadline: 10 * time.Second, want: 10 * time.Second},
+		{name: "longer than the write timeout", writeTimeout: 15 * time.Second, deadline: 30 * time.Second, want: 10 * time.Second, wantCapped: true},
+		{name: "no deadline", writeTimeout: 15 * time.Second, want: 10 * time.Second, wantCapped: true},
+		{name: "no write timeout", deadline: time.Minute, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := &Config{WriteTimeout: tt.writeTimeout, FetchRetryDeadline: tt.deadline}
+			if capped := cfg.CapFetchRetryDeadline(); capped != tt.wantCapped {
+				t.Errorf("CapFetchRetryDeadline() = %v, want %v", capped, tt.wantCapped)
+			}
+			if cfg.FetchRetryDeadline != tt.want {
+				t.Errorf("FetchRetryDeadline = %v, want %v", cfg.FetchRetryDeadline, tt.want)
+			}
+		})
+	}
+}
@@ -3,16 +3,20 @@ package errors
 import (
 	"errors"
 	"fmt"
+	"time"
 )
 
 // Common errors
 var (
-	ErrInvalidURL      = errors.New("invalid URL format")
-	ErrEmptyURL        = errors.New("URL cannot be empty")
-	ErrFetchTimeout    = errors.New("RSS feed fetch timeout")
-	ErrInvalidRSSXML   = errors.New("invalid RSS XML format")
-	ErrNoRSSItems      = errors.New("no items found in RSS feed")
-	ErrCSVWriteFailed  = errors.New("failed to write CSV")
+	ErrInvalidURL        = errors.New("invalid URL format")
+	ErrEmptyURL          = errors.New("URL cannot be empty")
+	ErrFetchTimeout      = errors.New("RSS feed fetch timeout")
+	ErrInvalidRSSXML     = errors.New("invalid RSS XML format")
+	ErrNoRSSItems        = errors.New("no items found in RSS feed")
+	ErrCSVWriteFailed    = errors.New("failed to write CSV")
+	ErrUnsupportedFormat = errors.New("unsupported export format")
+	ErrBlockedAddress    = errors.New("destination address is not allowed")
+	ErrNoFeedFound       = errors.New("no feed found on HTML page")
 )
 
 // ValidationError represents a validation error with field information
@@ -30,15 +34,48 @@ type FetchError struct {
 	URL        string
 	StatusCode int
 	Err        error
+	// Attempts is how many requests were made before giving up
+	Attempts int
 }
 
 func (e FetchError) Error() string {
+	msg := fmt.Sprintf("failed to fetch RSS from %s: %v", e.URL, e.Err)
 	if e.StatusCode > 0 {
-		return fmt.Sprintf("failed to fetch RSS from %s (status: %d): %v", e.URL, e.StatusCode, e.Err)
+		msg = fmt.Sprintf("failed to fetch RSS from %s (status: %d): %v", e.URL, e.StatusCode, e.Err)
 	}
-	return fmt.Sprintf("failed to fetch RSS from %s: %v", e.URL, e.Err)
+	if e.Attempts > 1 {
+		msg += fmt.Sprintf(" (after %d attempts)", e.Attempts)
+	}
+	return msg
 }
 
 func (e FetchError) Unwrap() error {
 	return e.Err
-}
\ No newline at end of file
+}
+
+// FeedTooLargeError reports a feed whose body exceeds the configured size limit
+type FeedTooLargeError struct {
+	URL   string
+	Limit int64
+	// Size is the declared Content-Length, or zero when the limit was hit while reading
+	Size int64
+}
+
+func (e FeedTooLargeError) Error() string {
+	if e.Size > 0 {
+		return fmt.Sprintf("feed at %s is too large: %d bytes exceeds the limit of %d bytes", e.URL, e.Size, e.Limit)
+	}
+	return fmt.Sprintf("feed at %s is too large: exceeds the limit of %d bytes", e.URL, e.Limit)
+}
+
+// CircuitOpenError reports a request rejected because the host's circuit
+// breaker is open after repeated failures
+type CircuitOpenError struct {
+	Host    string
+	RetryAt time.Time
+}
+
+func (e CircuitOpenError) Error() string {
+	return fmt.Sprintf("circuit breaker open for %s after repeated failures, retry after %s",
+		e.Host, e.RetryAt.UTC().Format(time.RFC3339))
+}
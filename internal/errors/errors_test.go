@@ -3,6 +3,7 @@ package errors
 import (
 	"errors"
 	"testing"
+	"time"
 )
 
 func TestValidationError(t *testing.T) {
@@ -40,6 +41,16 @@ func TestFetchError(t *testing.T) {
 			},
 			expected: "failed to fetch RSS from https://example.com/feed: connection timeout",
 		},
+		{
+			name: "with attempts",
+			err: FetchError{
+				URL:        "https://example.com/feed",
+				StatusCode: 503,
+				Err:        errors.New("service unavailable"),
+				Attempts:   3,
+			},
+			expected: "failed to fetch RSS from https://example.com/feed (status: 503): service unavailable (after 3 attempts)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -63,6 +74,45 @@ func TestFetchError_Unwrap(t *testing.T) {
 	}
 }
 
+func TestFeedTooLargeError(t *testing.T) {
+	tests := []struct {
+		name     string
+		err      FeedTooLargeError
+		expected string
+	}{
+		{
+			name:     "with declared size",
+			err:      FeedTooLargeError{URL: "https://example.com/feed", Limit: 1024, Size: 4096},
+			expected: "feed at https://example.com/feed is too large: 4096 bytes exceeds the limit of 1024 bytes",
+		},
+		{
+			name:     "without declared size",
+			err:      FeedTooLargeError{URL: "https://example.com/feed", Limit: 1024},
+			expected: "feed at https://example.com/feed is too large: exceeds the limit of 1024 bytes",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.err.Error(); got != tt.expected {
+				t.Errorf("Error() = %q, want %q", got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCircuitOpenError(t *testing.T) {
+	err := CircuitOpenError{
+		Host:    "example.com",
+		RetryAt: time.Date(2024, 1, 1, 12, 0, 30, 0, time.UTC),
+	}
+
+	expected := "circuit breaker open for example.com after repeated failures, retry after 2024-01-01T12:00:30Z"
+	if err.Error() != expected {
+		t.Errorf("Error() = %q, want %q", err.Error(), expected)
+	}
+}
+
 func TestCommonErrors(t *testing.T) {
 	// Test that common errors are defined
 	commonErrors := []struct {
@@ -75,6 +125,9 @@ func TestCommonErrors(t *testing.T) {
 		{ErrInvalidRSSXML, "invalid RSS XML format"},
 		{ErrNoRSSItems, "no items found in RSS feed"},
 		{ErrCSVWriteFailed, "failed to write CSV"},
+		{ErrUnsupportedFormat, "unsupported export format"},
+		{ErrBlockedAddress, "destination address is not allowed"},
+		{ErrNoFeedFound, "no feed found on HTML page"},
 	}
 
 	for _, ce := range commonErrors {
@@ -85,4 +138,4 @@ func TestCommonErrors(t *testing.T) {
 			t.Errorf("Common error = %q, want %q", ce.err.Error(), ce.desc)
 		}
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,63 @@
+package handlers
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strings"
+
+	"rss-feed-to-csv/internal/services"
+)
+
+// HandleBreakerStatus reports the circuit breaker state of every upstream
+// host with recent failures. The endpoint requires the configured admin
+// token as a bearer token and is hidden when no token is set.
+func (h *Handler) HandleBreakerStatus(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	if r.Method != http.MethodGet {
+		w.Header().Set("Allow", http.MethodGet)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	hosts := h.breaker.Status()
+	if hosts == nil {
+		hosts = []services.BreakerStatus{}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	response := struct {
+		Enabled bool                     `json:"enabled"`
+		Hosts   []services.BreakerStatus `json:"hosts"`
+	}{
+		Enabled: h.breaker != nil,
+		Hosts:   hosts,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("[ERROR] Failed to write breaker status - Error: %v, Client: %s", err, r.RemoteAddr)
+	}
+}
+
+// authorizeAdmin checks the admin bearer token, writing the error response
+// and returning false when the request may not proceed. Admin endpoints
+// answer 404 when no token is configured.
+func (h *Handler) authorizeAdmin(w http.ResponseWriter, r *http.Request) bool {
+	if h.adminToken == "" {
+		http.NotFound(w, r)
+		return false
+	}
+
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) != 1 {
+		log.Printf("[ERROR] Unauthorized admin request - Path: %s, Client: %s", r.URL.Path, r.RemoteAddr)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		http.Error(w, "Unauthorized", http.StatusUnauthorized)
+		return false
+	}
+	return true
+}
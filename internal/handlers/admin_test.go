@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"rss-feed-to-csv/internal/config"
+)
+
+func TestAuthorizeAdmin(t *testing.T) {
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		wantOK        bool
+		wantStatus    int
+	}{
+		{name: "no token configured", authorization: "Bearer ", wantStatus: http.StatusNotFound},
+		{name: "missing header", token: "secret", wantStatus: http.StatusUnauthorized},
+		{name: "wrong token", token: "secret", authorization: "Bearer guess", wantStatus: http.StatusUnauthorized},
+		{name: "not a bearer token", token: "secret", authorization: "Basic secret", wantStatus: http.StatusUnauthorized},
+		{name: "valid token", token: "secret", authorization: "Bearer secret", wantOK: true, wantStatus: http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			handler := newTestHandler(t, func(cfg *config.Config) { cfg.AdminToken = tt.token })
+			req := httptest.NewRequest(http.MethodGet, "/admin/breakers", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rec := httptest.NewRecorder()
+
+			if ok := handler.authorizeAdmin(rec, req); ok != tt.wantOK {
+				t.Errorf("authorizeAdmin() = %v, want %v", ok, tt.wantOK)
+			}
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if tt.wantStatus == http.StatusUnauthorized && rec.Header().Get("WWW-Authenticate") != "Bearer" {
+				t.Errorf("WWW-Authenticate = %q, want Bearer", rec.Header().Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
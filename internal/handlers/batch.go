@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+	"rss-feed-to-csv/internal/services"
+)
+
+// maxBatchBodySize bounds the request body of a batch export
+const maxBatchBodySize = 1 << 20
+
+// HandleBatchExport merges the items of several feeds into one export. The
+// POST body lists the feeds as a JSON object {"urls": [...]}, an OPML
+// document or plain text with one URL per line; the query string takes the
+// same export options as /export. Feeds that fail are reported in a summary
+// after the items instead of failing the whole export.
+func (h *Handler) HandleBatchExport(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		w.Header().Set("Allow", http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Add("Vary", "Accept")
+	format, exporter, err := h.exporters.Negotiate(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+	if err != nil {
+		log.Printf("[ERROR] Unsupported export format - Error: %v, Client: %s", err, r.RemoteAddr)
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+
+	opts, err := parseExportOptions(r.URL.Query())
+	if err == nil && opts.Mode == services.ModeChannel {
+		err = &errors.ValidationError{Field: "mode", Message: "batch exports only support items"}
+	}
+	var filter *services.Filter
+	if err == nil {
+		filter, err = parseFilter(r.URL.Query())
+	}
+	if err != nil {
+		log.Printf("[ERROR] Invalid export options - Error: %v, Client: %s", err, r.RemoteAddr)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	urls, err := readBatchURLs(w, r)
+	if err == nil && len(urls) > h.batchMaxFeeds {
+		err = &errors.ValidationError{
+			Field:   "urls",
+			Message: fmt.Sprintf("%d feeds exceed the limit of %d", len(urls), h.batchMaxFeeds),
+		}
+	}
+	if err != nil {
+		log.Printf("[ERROR] Invalid batch request - Error: %v, Client: %s", err, r.RemoteAddr)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Invalid URLs are reported like failed fetches, keeping the input order
+	reports := make([]services.FeedReport, len(urls))
+	var valid []string
+	var positions []int
+	for i, url := range urls {
+		url = h.validator.SanitizeInput(url)
+		reports[i].URL = url
+		if err := h.validator.ValidateURL(url); err != nil {
+			reports[i].Err = err
+			continue
+		}
+		valid = append(valid, url)
+		positions = append(positions, i)
+	}
+
+	log.Printf("[INFO] Fetching batch - Feeds: %d, Invalid: %d, Client: %s",
+		len(urls), len(urls)-len(valid), r.RemoteAddr)
+
+	ctx, cancel := h.batchDeadline(w, r)
+	defer cancel()
+
+	rss := &models.RSS{}
+	if len(valid) > 0 {
+		var fetched []services.FeedReport
+		rss, fetched = h.rssFetcher.FetchBatch(ctx, valid, h.batchConcurrency, parseFetchOptions(r.URL.Query()))
+		for i, report := range fetched {
+			reports[positions[i]] = report
+		}
+	}
+
+	failures := 0
+	for i, report := range reports {
+		if report.Err != nil {
+			failures++
+			log.Printf("[WARN] Batch feed failed - URL: %s, Error: %v, Client: %s", report.URL, report.Err, r.RemoteAddr)
+			if stderrors.Is(report.Err, errors.ErrBlockedAddress) {
+				reports[i].Err = errors.ErrBlockedAddress
+			}
+			continue
+		}
+		h.feeds.Record(models.OPMLFeed{URL: report.FeedURL, Title: report.Title})
+	}
+
+	// The summary counts the items fetched from each feed, before filtering
+	if filter != nil {
+		filter.Apply(rss)
+	}
+
+	opts.IncludeFeedColumns = false
+	opts.BatchColumns = true
+	opts.Summary = reports
+
+	w.Header().Set("X-Batch-Feeds", strconv.Itoa(len(reports)))
+	w.Header().Set("X-Batch-Failures", strconv.Itoa(failures))
+	w.Header().Set("Content-Type", exporter.ContentType())
+	w.Header().Set("Content-Disposition", "attachment; filename=feeds."+exporter.FileExtension())
+
+	if err := exporter.Export(r.Context(), w, rss, opts); err != nil {
+		log.Printf("[ERROR] Failed to export %s batch - Error: %v, Client: %s", format, err, r.RemoteAddr)
+		// Note: Headers already sent, can't return HTTP error
+		return
+	}
+
+	log.Printf("[SUCCESS] %s batch export completed - Feeds: %d, Failed: %d, Rows selected: %d, Client: %s",
+		format, len(reports), failures, opts.RowCount(rss), r.RemoteAddr)
+}
+
+// batchDeadline bounds the fetches of a batch by the batch timeout. A batch
+// usually outlasts the server's write timeout, so the response's write
+// deadline is moved to the write timeout after the batch deadline. The
+// export itself runs on the request context, so feeds fetched in time are
+// still written when the batch deadline passes.
+func (h *Handler) batchDeadline(w http.ResponseWriter, r *http.Request) (context.Context, context.CancelFunc) {
+	if h.batchTimeout <= 0 {
+		return context.WithCancel(r.Context())
+	}
+	deadline := time.Now().Add(h.batchTimeout)
+	if h.writeTimeout > 0 {
+		err := http.NewResponseController(w).SetWriteDeadline(deadline.Add(h.writeTimeout))
+		if err != nil && !stderrors.Is(err, http.ErrNotSupported) {
+			log.Printf("[WARN] Failed to extend write deadline - Error: %v, Client: %s", err, r.RemoteAddr)
+		}
+	}
+	return context.WithDeadline(r.Context(), deadline)
+}
+
+// readBatchURLs reads the feed URLs of a batch request body, dropping duplicates
+func readBatchURLs(w http.ResponseWriter, r *http.Request) ([]string, error) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBatchBodySize))
+	if err != nil {
+		return nil, &errors.ValidationError{Field: "body", Message: err.Error()}
+	}
+
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	trimmed := bytes.TrimSpace(body)
+	var urls []string
+	// The body is sniffed as well as the content type, since curl -d sends
+	// JSON and OPML as form-urlencoded by default
+	switch {
+	case mediaType == "application/json" || bytes.HasPrefix(trimmed, []byte("{")):
+		var request struct {
+			URLs []string `json:"urls"`
+		}
+		if err := json.Unmarshal(body, &request); err != nil {
+			return nil, &errors.ValidationError{Field: "body", Message: "invalid JSON: " + err.Error()}
+		}
+		urls = request.URLs
+	case strings.HasSuffix(mediaType, "xml") || mediaType == "text/x-opml" || bytes.HasPrefix(trimmed, []byte("<")):
+		opml, err := decodeOPML(body)
+		if err != nil {
+			return nil, err
+		}
+		for _, feed := range opml.Feeds() {
+			urls = append(urls, feed.URL)
+		}
+	default:
+		scanner := bufio.NewScanner(bytes.NewReader(body))
+		for scanner.Scan() {
+			line := strings.TrimSpace(scanner.Text())
+			if line != "" && !strings.HasPrefix(line, "#") {
+				urls = append(urls, line)
+			}
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, &errors.ValidationError{Field: "body", Message: err.Error()}
+		}
+	}
+
+	seen := make(map[string]bool, len(urls))
+	unique := urls[:0]
+	for _, url := range urls {
+		url = strings.TrimSpace(url)
+		if url != "" && !seen[url] {
+			seen[url] = true
+			unique = append(unique, url)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, &errors.ValidationError{Field: "urls", Message: "at least one feed URL is required"}
+	}
+	return unique, nil
+}
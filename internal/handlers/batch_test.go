@@ -0,0 +1,214 @@
+package handlers
+
+import (
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"rss-feed-to-csv/internal/config"
+)
+
+// batchResponse is the JSON form of a batch export
+type batchResponse struct {
+	Items   []map[string]string `json:"items"`
+	Summary []struct {
+		FeedURL string
+		Status  string
+		Items   int
+		Error   string
+	} `json:"summary"`
+}
+
+// decodeBatch decodes a JSON batch export, failing the test on a non-200 response
+func decodeBatch(t *testing.T, rec *httptest.ResponseRecorder) batchResponse {
+	t.Helper()
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var response batchResponse
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("invalid JSON response: %v\n%s", err, rec.Body.String())
+	}
+	return response
+}
+
+func TestHandleBatchExport_Bodies(t *testing.T) {
+	feeds := newFeedServer(t, 0)
+	handler := newTestHandler(t, nil)
+	a, b := feeds.URL+"/a.xml", feeds.URL+"/b.xml"
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+	}{
+		{
+			name:        "JSON",
+			contentType: "application/json",
+			body:        `{"urls": ["` + a + `", "ftp://example.com/feed", "` + b + `", "` + a + `"]}`,
+		},
+		{
+			name:        "JSON sent as a form",
+			contentType: "application/x-www-form-urlencoded",
+			body:        ` {"urls": ["` + a + `", "ftp://example.com/feed", "` + b + `"]}`,
+		},
+		{
+			name:        "OPML",
+			contentType: "text/x-opml",
+			body: `<opml version="2.0"><body><outline text="Folder">` +
+				`<outline type="rss" xmlUrl="` + a + `"/><outline type="rss" xmlUrl="ftp://example.com/feed"/>` +
+				`</outline><outline type="rss" xmlUrl="` + b + `"/></body></opml>`,
+		},
+		{
+			name:        "plain text",
+			contentType: "text/plain",
+			body:        "# morning feeds\n" + a + "\n\nftp://example.com/feed\n" + b + "\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/export/batch?format=json", strings.NewReader(tt.body))
+			req.Header.Set("Content-Type", tt.contentType)
+			rec := httptest.NewRecorder()
+			handler.HandleBatchExport(rec, req)
+
+			response := decodeBatch(t, rec)
+			if got := rec.Header().Get("X-Batch-Feeds"); got != "3" {
+				t.Errorf("X-Batch-Feeds = %s, want 3", got)
+			}
+			if got := rec.Header().Get("X-Batch-Failures"); got != "1" {
+				t.Errorf("X-Batch-Failures = %s, want 1", got)
+			}
+
+			var titles []string
+			for _, item := range response.Items {
+				titles = append(titles, item["Title"])
+				if !strings.HasPrefix(item["FeedURL"], feeds.URL) {
+					t.Errorf("item FeedURL = %q, want the feed it came from", item["FeedURL"])
+				}
+			}
+			if strings.Join(titles, "|") != "Item of /a.xml|Item of /b.xml" {
+				t.Errorf("item titles = %q, want the items of a and b in order", titles)
+			}
+
+			// The invalid URL is reported in its place in the summary
+			if len(response.Summary) != 3 {
+				t.Fatalf("summary = %+v, want 3 feeds", response.Summary)
+			}
+			for i, want := range []string{"ok", "error", "ok"} {
+				if response.Summary[i].Status != want {
+					t.Errorf("summary[%d] = %+v, want status %s", i, response.Summary[i], want)
+				}
+			}
+			if response.Summary[0].Items != 1 || !strings.Contains(response.Summary[1].Error, "scheme") {
+				t.Errorf("summary = %+v, want 1 item for a and a scheme error", response.Summary)
+			}
+		})
+	}
+}
+
+func TestHandleBatchExport_Errors(t *testing.T) {
+	handler := newTestHandler(t, func(cfg *config.Config) { cfg.BatchMaxFeeds = 2 })
+
+	tests := []struct {
+		name   string
+		method string
+		query  string
+		body   string
+		want   int
+	}{
+		{name: "GET", method: http.MethodGet, want: http.StatusMethodNotAllowed},
+		{name: "no URLs", method: http.MethodPost, body: "\n# nothing\n", want: http.StatusBadRequest},
+		{name: "too many feeds", method: http.MethodPost, body: "https://a.example/\nhttps://b.example/\nhttps://c.example/", want: http.StatusBadRequest},
+		{name: "channel mode", method: http.MethodPost, query: "?mode=channel", body: "https://a.example/", want: http.StatusBadRequest},
+		{name: "invalid OPML", method: http.MethodPost, body: "<opml><body>", want: http.StatusBadRequest},
+		{name: "unknown format", method: http.MethodPost, query: "?format=pdf", body: "https://a.example/", want: http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/export/batch"+tt.query, strings.NewReader(tt.body))
+			rec := httptest.NewRecorder()
+			handler.HandleBatchExport(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleBatchExport_BlockedAddress(t *testing.T) {
+	feeds := newFeedServer(t, 0)
+	handler := newTestHandler(t, func(cfg *config.Config) { cfg.AllowedCIDRs = nil })
+
+	req := httptest.NewRequest(http.MethodPost, "/export/batch?format=json", strings.NewReader(feeds.URL+"/a.xml"))
+	rec := httptest.NewRecorder()
+	handler.HandleBatchExport(rec, req)
+
+	response := decodeBatch(t, rec)
+	if len(response.Summary) != 1 || response.Summary[0].Error != "destination address is not allowed" {
+		t.Errorf("summary = %+v, want the generic blocked message without the address", response.Summary)
+	}
+}
+
+func TestHandleBatchExport_Deadline(t *testing.T) {
+	feeds := newFeedServer(t, 5*time.Second)
+	handler := newTestHandler(t, func(cfg *config.Config) { cfg.BatchTimeout = 200 * time.Millisecond })
+
+	body := feeds.URL + "/a.xml\n" + feeds.URL + "/slow/b.xml\n"
+	req := httptest.NewRequest(http.MethodPost, "/export/batch?format=json", strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	start := time.Now()
+	handler.HandleBatchExport(rec, req)
+
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("batch took %v, want it cut off by the batch timeout", elapsed)
+	}
+	response := decodeBatch(t, rec)
+	if len(response.Items) != 1 || response.Items[0]["Title"] != "Item of /a.xml" {
+		t.Errorf("items = %v, want only the feed fetched in time", response.Items)
+	}
+	if len(response.Summary) != 2 || response.Summary[0].Status != "ok" || response.Summary[1].Status != "error" {
+		t.Errorf("summary = %+v, want the slow feed reported as failed", response.Summary)
+	}
+}
+
+func TestHandleBatchExport_OutlastsWriteTimeout(t *testing.T) {
+	feeds := newFeedServer(t, 600*time.Millisecond)
+	const writeTimeout = 300 * time.Millisecond
+	handler := newTestHandler(t, func(cfg *config.Config) { cfg.WriteTimeout = writeTimeout })
+
+	// Serve the handler with the same write timeout a real server applies
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(handler.HandleBatchExport), WriteTimeout: writeTimeout}
+	go server.Serve(listener)
+	defer server.Close()
+
+	body := feeds.URL + "/slow/a.xml\n" + feeds.URL + "/slow/b.xml\n"
+	resp, err := http.Post("http://"+listener.Addr().String()+"/export/batch?format=json", "text/plain", strings.NewReader(body))
+	if err != nil {
+		t.Fatalf("batch request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("reading the batch response failed: %v", err)
+	}
+
+	var response batchResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		t.Fatalf("invalid JSON response: %v\n%s", err, data)
+	}
+	if len(response.Items) != 2 {
+		t.Errorf("items = %v, want both slow feeds", response.Items)
+	}
+}
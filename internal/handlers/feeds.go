@@ -0,0 +1,135 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+)
+
+// HandleFeeds exports and imports the server's feed list as OPML. GET
+// returns the saved feeds and the feeds recently exported, or only the
+// saved ones with saved=true. POST saves the feeds of an OPML document;
+// outlines whose URL fails validation are skipped and reported. The
+// endpoint requires the admin token like the other admin endpoints.
+func (h *Handler) HandleFeeds(w http.ResponseWriter, r *http.Request) {
+	if !h.authorizeAdmin(w, r) {
+		return
+	}
+
+	switch r.Method {
+	case http.MethodGet:
+		h.exportFeeds(w, r)
+	case http.MethodPost:
+		h.importFeeds(w, r)
+	default:
+		w.Header().Set("Allow", http.MethodGet+", "+http.MethodPost)
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// exportFeeds writes the feed list as an OPML download
+func (h *Handler) exportFeeds(w http.ResponseWriter, r *http.Request) {
+	savedOnly := false
+	if value := r.URL.Query().Get("saved"); value != "" {
+		var err error
+		if savedOnly, err = strconv.ParseBool(value); err != nil {
+			http.Error(w, (&errors.ValidationError{Field: "saved", Message: "must be true or false"}).Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	feeds := h.feeds.Feeds(savedOnly)
+	data, err := xml.MarshalIndent(models.NewOPML("RSS to CSV feeds", feeds), "", "  ")
+	if err != nil {
+		log.Printf("[ERROR] Failed to build OPML - Error: %v, Client: %s", err, r.RemoteAddr)
+		http.Error(w, "Failed to build OPML", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+	w.Header().Set("Content-Disposition", "attachment; filename=feeds.opml")
+	w.Header().Set("Cache-Control", "no-store")
+	if _, err := io.WriteString(w, xml.Header); err == nil {
+		_, err = w.Write(append(data, '\n'))
+	}
+	if err != nil {
+		log.Printf("[ERROR] Failed to write OPML - Error: %v, Client: %s", err, r.RemoteAddr)
+		return
+	}
+	log.Printf("[SUCCESS] OPML export completed - Feeds: %d, Saved only: %v, Client: %s", len(feeds), savedOnly, r.RemoteAddr)
+}
+
+// importFeeds saves the valid feeds of an OPML request body
+func (h *Handler) importFeeds(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxBatchBodySize))
+	var opml *models.OPML
+	if err != nil {
+		err = &errors.ValidationError{Field: "body", Message: err.Error()}
+	} else {
+		opml, err = decodeOPML(body)
+	}
+	if err != nil {
+		log.Printf("[ERROR] Invalid OPML import - Error: %v, Client: %s", err, r.RemoteAddr)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	type rejectedFeed struct {
+		URL   string `json:"url"`
+		Error string `json:"error"`
+	}
+	var valid []models.OPMLFeed
+	rejected := []rejectedFeed{}
+	for _, feed := range opml.Feeds() {
+		feed.URL = h.validator.SanitizeInput(feed.URL)
+		if err := h.validator.ValidateURL(feed.URL); err != nil {
+			rejected = append(rejected, rejectedFeed{URL: feed.URL, Error: err.Error()})
+			continue
+		}
+		valid = append(valid, feed)
+	}
+
+	if err := h.feeds.Save(valid); err != nil {
+		log.Printf("[ERROR] Failed to save feeds - Error: %v, Client: %s", err, r.RemoteAddr)
+		http.Error(w, "Failed to save feeds", http.StatusInternalServerError)
+		return
+	}
+	log.Printf("[SUCCESS] OPML import completed - Saved: %d, Rejected: %d, Client: %s", len(valid), len(rejected), r.RemoteAddr)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.Header().Set("Cache-Control", "no-store")
+	response := struct {
+		Saved    int            `json:"saved"`
+		Rejected []rejectedFeed `json:"rejected"`
+	}{
+		Saved:    len(valid),
+		Rejected: rejected,
+	}
+	if err := json.NewEncoder(w).Encode(response); err != nil {
+		log.Printf("[ERROR] Failed to write import result - Error: %v, Client: %s", err, r.RemoteAddr)
+	}
+}
+
+// decodeOPML parses an OPML document from a request body
+func decodeOPML(body []byte) (*models.OPML, error) {
+	var opml models.OPML
+	if err := xml.Unmarshal(body, &opml); err != nil {
+		return nil, &errors.ValidationError{Field: "body", Message: "invalid OPML: " + err.Error()}
+	}
+	return &opml, nil
+}
+
+// recordFeed remembers an exported feed for the OPML feed list
+func (h *Handler) recordFeed(feedURL string, rss *models.RSS) {
+	h.feeds.Record(models.OPMLFeed{
+		URL:     feedURL,
+		Title:   rss.Channel.Title,
+		SiteURL: rss.Channel.GetLink(),
+	})
+}
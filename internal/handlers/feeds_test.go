@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"rss-feed-to-csv/internal/config"
+	"rss-feed-to-csv/internal/models"
+)
+
+// adminRequest builds an admin request carrying the test token
+func adminRequest(method, target, body string) *http.Request {
+	req := httptest.NewRequest(method, target, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer secret")
+	return req
+}
+
+// listedFeeds fetches the OPML feed list, failing the test on a non-200 response
+func listedFeeds(t *testing.T, handler *Handler, query string) []models.OPMLFeed {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	handler.HandleFeeds(rec, adminRequest(http.MethodGet, "/admin/feeds"+query, ""))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("GET status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	if got := rec.Header().Get("Content-Type"); !strings.HasPrefix(got, "text/x-opml") {
+		t.Errorf("Content-Type = %q, want text/x-opml", got)
+	}
+	var opml models.OPML
+	if err := xml.Unmarshal(rec.Body.Bytes(), &opml); err != nil {
+		t.Fatalf("invalid OPML response: %v\n%s", err, rec.Body.String())
+	}
+	return opml.Feeds()
+}
+
+// feedURLs returns the URLs of feeds
+func feedURLs(feeds []models.OPMLFeed) []string {
+	urls := make([]string, len(feeds))
+	for i, feed := range feeds {
+		urls[i] = feed.URL
+	}
+	return urls
+}
+
+func TestHandleFeeds_ImportAndExport(t *testing.T) {
+	feeds := newFeedServer(t, 0)
+	path := filepath.Join(t.TempDir(), "feeds.opml")
+	configure := func(cfg *config.Config) {
+		cfg.AdminToken = "secret"
+		cfg.FeedsFile = path
+	}
+	handler := newTestHandler(t, configure)
+
+	opml := `<opml version="2.0"><body><outline text="News">` +
+		`<outline type="rss" text="Alpha" xmlUrl="https://alpha.example/feed"/>` +
+		`<outline type="rss" text="Local" xmlUrl="file:///etc/passwd"/>` +
+		`</outline><outline type="rss" text="Beta" xmlUrl="https://beta.example/rss"/></body></opml>`
+	rec := httptest.NewRecorder()
+	handler.HandleFeeds(rec, adminRequest(http.MethodPost, "/admin/feeds", opml))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("POST status = %d, want 200; body: %s", rec.Code, rec.Body.String())
+	}
+	var result struct {
+		Saved    int `json:"saved"`
+		Rejected []struct {
+			URL   string `json:"url"`
+			Error string `json:"error"`
+		} `json:"rejected"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &result); err != nil {
+		t.Fatalf("invalid JSON response: %v\n%s", err, rec.Body.String())
+	}
+	if result.Saved != 2 || len(result.Rejected) != 1 || result.Rejected[0].URL != "file:///etc/passwd" || result.Rejected[0].Error == "" {
+		t.Errorf("import result = %+v, want 2 saved and the file URL rejected", result)
+	}
+
+	// Exporting a feed adds it to the full list but not to the saved one
+	req := httptest.NewRequest(http.MethodGet, "/export?url="+url.QueryEscape(feeds.URL+"/recent.xml"), nil)
+	export := httptest.NewRecorder()
+	handler.HandleExport(export, req)
+	if export.Code != http.StatusOK {
+		t.Fatalf("export status = %d; body: %s", export.Code, export.Body.String())
+	}
+
+	all := feedURLs(listedFeeds(t, handler, ""))
+	want := []string{"https://alpha.example/feed", "https://beta.example/rss", feeds.URL + "/recent.xml"}
+	if strings.Join(all, " ") != strings.Join(want, " ") {
+		t.Errorf("feeds = %q, want %q", all, want)
+	}
+	saved := listedFeeds(t, handler, "?saved=true")
+	if len(saved) != 2 || saved[0].Title != "Alpha" || len(saved[0].Categories) != 1 || saved[0].Categories[0] != "News" {
+		t.Errorf("saved feeds = %+v, want Alpha in News and Beta", saved)
+	}
+
+	// Saved feeds survive a restart, exported ones do not
+	reloaded := feedURLs(listedFeeds(t, newTestHandler(t, configure), ""))
+	if strings.Join(reloaded, " ") != strings.Join(want[:2], " ") {
+		t.Errorf("feeds after reload = %q, want %q", reloaded, want[:2])
+	}
+}
+
+func TestHandleFeeds_Errors(t *testing.T) {
+	handler := newTestHandler(t, func(cfg *config.Config) { cfg.AdminToken = "secret" })
+
+	tests := []struct {
+		name   string
+		method string
+		target string
+		body   string
+		want   int
+	}{
+		{name: "invalid saved flag", method: http.MethodGet, target: "/admin/feeds?saved=maybe", want: http.StatusBadRequest},
+		{name: "invalid OPML", method: http.MethodPost, target: "/admin/feeds", body: "not opml", want: http.StatusBadRequest},
+		{name: "unsupported method", method: http.MethodDelete, target: "/admin/feeds", want: http.StatusMethodNotAllowed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			handler.HandleFeeds(rec, adminRequest(tt.method, tt.target, tt.body))
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d; body: %s", rec.Code, tt.want, rec.Body.String())
+			}
+		})
+	}
+
+	// Without the token the endpoint is not reachable
+	rec := httptest.NewRecorder()
+	handler.HandleFeeds(rec, httptest.NewRequest(http.MethodGet, "/admin/feeds", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("status without token = %d, want 401", rec.Code)
+	}
+}
@@ -1,28 +1,86 @@
 package handlers
 
 import (
+	stderrors "errors"
+	"fmt"
 	"log"
+	"math"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"rss-feed-to-csv/internal/config"
+	"rss-feed-to-csv/internal/errors"
 	"rss-feed-to-csv/internal/services"
 	"rss-feed-to-csv/internal/validator"
 )
 
 // Handler contains all HTTP handlers for the application
 type Handler struct {
-	rssFetcher  *services.RSSFetcher
-	csvExporter *services.CSVExporter
-	validator   *validator.URLValidator
+	rssFetcher *services.RSSFetcher
+	exporters  *services.ExporterRegistry
+	validator  *validator.URLValidator
+	breaker    *services.CircuitBreaker
+	adminToken string
+	feeds      *services.FeedDirectory
+
+	writeTimeout     time.Duration
+	batchMaxFeeds    int
+	batchConcurrency int
+	batchTimeout     time.Duration
 }
 
 // NewHandler creates a new handler with dependencies
-func NewHandler(cfg *config.Config) *Handler {
-	return &Handler{
-		rssFetcher:  services.NewRSSFetcher(cfg.RSSFetchTimeout, cfg.UserAgent),
-		csvExporter: services.NewCSVExporter(),
-		validator:   validator.NewURLValidator(cfg.MaxURLLength),
+func NewHandler(cfg *config.Config) (*Handler, error) {
+	policy, err := validator.NewAddressPolicy(cfg.BlockedCIDRs, cfg.AllowedCIDRs, cfg.AllowedHosts)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address policy: %w", err)
+	}
+
+	cache, err := services.NewFeedCache(cfg.CacheBackend, cfg.CacheDir, services.CacheLimits{
+		MaxEntries: cfg.CacheMaxEntries,
+		MaxBytes:   cfg.CacheMaxBytes,
+		Retention:  cfg.CacheTTL,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed cache: %w", err)
 	}
+
+	feeds, err := services.NewFeedDirectory(cfg.FeedsFile, cfg.FeedsMaxKnown)
+	if err != nil {
+		return nil, fmt.Errorf("invalid feed list: %w", err)
+	}
+
+	breaker := services.NewCircuitBreaker(cfg.BreakerFailureThreshold, cfg.BreakerOpenTimeout)
+
+	return &Handler{
+		rssFetcher: services.NewRSSFetcher(services.FetcherOptions{
+			Timeout:       cfg.RSSFetchTimeout,
+			UserAgent:     cfg.UserAgent,
+			MaxSize:       cfg.MaxRSSSize,
+			AddressPolicy: policy,
+			Cache:         cache,
+			CacheTTL:      cfg.CacheTTL,
+			Retry: services.RetryPolicy{
+				MaxAttempts: cfg.FetchMaxAttempts,
+				BaseDelay:   cfg.FetchRetryBaseDelay,
+				MaxDelay:    cfg.FetchRetryMaxDelay,
+				Deadline:    cfg.FetchRetryDeadline,
+			},
+			Breaker: breaker,
+		}),
+		exporters:  services.DefaultExporterRegistry(),
+		validator:  validator.NewURLValidator(cfg.MaxURLLength),
+		breaker:    breaker,
+		adminToken: cfg.AdminToken,
+		feeds:      feeds,
+
+		writeTimeout:     cfg.WriteTimeout,
+		batchMaxFeeds:    cfg.BatchMaxFeeds,
+		batchConcurrency: cfg.BatchConcurrency,
+		batchTimeout:     cfg.BatchTimeout,
+	}, nil
 }
 
 // HandleIndex serves the main HTML page
@@ -35,42 +93,115 @@ func (h *Handler) HandleExport(w http.ResponseWriter, r *http.Request) {
 	// Extract and validate query parameters
 	rssURL := r.URL.Query().Get("url")
 	rssURL = h.validator.SanitizeInput(rssURL)
-	
+
 	if err := h.validator.ValidateURL(rssURL); err != nil {
 		log.Printf("[ERROR] Invalid URL - URL: %s, Error: %v, Client: %s", rssURL, err, r.RemoteAddr)
 		http.Error(w, "Invalid URL: "+err.Error(), http.StatusBadRequest)
 		return
 	}
-	
-	sanitize := r.URL.Query().Get("sanitize") == "true"
-	
-	log.Printf("[INFO] Fetching RSS feed - URL: %s, Client: %s, User-Agent: %s", 
+
+	// Pick the output format from the format parameter or the Accept header
+	w.Header().Add("Vary", "Accept")
+	format, exporter, err := h.exporters.Negotiate(r.URL.Query().Get("format"), r.Header.Get("Accept"))
+	if err != nil {
+		log.Printf("[ERROR] Unsupported export format - Error: %v, Client: %s", err, r.RemoteAddr)
+		http.Error(w, err.Error(), http.StatusNotAcceptable)
+		return
+	}
+
+	opts, err := parseExportOptions(r.URL.Query())
+	var filter *services.Filter
+	if err == nil {
+		filter, err = parseFilter(r.URL.Query())
+	}
+	if err != nil {
+		log.Printf("[ERROR] Invalid export options - URL: %s, Error: %v, Client: %s", rssURL, err, r.RemoteAddr)
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	log.Printf("[INFO] Fetching RSS feed - URL: %s, Client: %s, User-Agent: %s",
 		rssURL, r.RemoteAddr, r.Header.Get("User-Agent"))
 
 	// Fetch RSS feed
-	rss, err := h.rssFetcher.FetchRSS(r.Context(), rssURL)
+	result, err := h.rssFetcher.FetchFeed(r.Context(), rssURL, parseFetchOptions(r.URL.Query()))
 	if err != nil {
-		log.Printf("[ERROR] Failed to fetch/parse RSS - URL: %s, Error: %v, Client: %s", 
+		log.Printf("[ERROR] Failed to fetch/parse RSS - URL: %s, Error: %v, Client: %s",
 			rssURL, err, r.RemoteAddr)
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		var circuitOpen *errors.CircuitOpenError
+		if stderrors.As(err, &circuitOpen) {
+			w.Header().Set("Retry-After", retryAfterSeconds(circuitOpen.RetryAt))
+		}
+		http.Error(w, clientError(err), fetchErrorStatus(err))
 		return
 	}
 
-	log.Printf("[INFO] Successfully parsed RSS feed - URL: %s, Items: %d, Sanitize: %v, Client: %s", 
-		rssURL, len(rss.Channel.Items), sanitize, r.RemoteAddr)
+	rss := result.RSS
+	// The feed may have been discovered from an HTML page at the requested URL
+	w.Header().Set("X-Feed-URL", result.FeedURL)
+	h.recordFeed(result.FeedURL, rss)
+	log.Printf("[INFO] Successfully parsed RSS feed - URL: %s, Items: %d, Sanitize: %v, Client: %s",
+		rssURL, len(rss.Channel.Items), opts.SanitizeHTML, r.RemoteAddr)
 
-	// Set response headers for CSV download
-	w.Header().Set("Content-Type", "text/csv")
-	w.Header().Set("Content-Disposition", "attachment; filename=feed.csv")
+	if filter != nil {
+		fetched := len(rss.Channel.Items)
+		filter.Apply(rss)
+		log.Printf("[INFO] Filtered items - URL: %s, Kept: %d of %d, Client: %s",
+			rssURL, len(rss.Channel.Items), fetched, r.RemoteAddr)
+	}
 
-	// Export to CSV
-	if err := h.csvExporter.Export(r.Context(), w, rss, sanitize); err != nil {
-		log.Printf("[ERROR] Failed to export CSV - URL: %s, Error: %v, Client: %s", 
-			rssURL, err, r.RemoteAddr)
+	if len(result.Warnings) > 0 {
+		log.Printf("[WARN] Repaired malformed feed - URL: %s, Warnings: %s, Client: %s",
+			rssURL, strings.Join(result.Warnings, "; "), r.RemoteAddr)
+		w.Header().Set("X-Feed-Warnings", strings.Join(result.Warnings, "; "))
+	}
+
+	// Set response headers for download
+	w.Header().Set("Content-Type", exporter.ContentType())
+	w.Header().Set("Content-Disposition", "attachment; filename=feed."+exporter.FileExtension())
+
+	if err := exporter.Export(r.Context(), w, rss, opts); err != nil {
+		log.Printf("[ERROR] Failed to export %s - URL: %s, Error: %v, Client: %s",
+			format, rssURL, err, r.RemoteAddr)
 		// Note: Headers already sent, can't return HTTP error
 		return
 	}
 
-	log.Printf("[SUCCESS] CSV export completed - URL: %s, Items exported: %d, Client: %s", 
-		rssURL, len(rss.Channel.Items), r.RemoteAddr)
-}
\ No newline at end of file
+	log.Printf("[SUCCESS] %s export completed - URL: %s, Rows selected: %d, Client: %s",
+		format, rssURL, opts.RowCount(rss), r.RemoteAddr)
+}
+
+// fetchErrorStatus maps a fetch or parse error to the HTTP status returned to the client
+func fetchErrorStatus(err error) int {
+	var tooLarge *errors.FeedTooLargeError
+	if stderrors.As(err, &tooLarge) {
+		return http.StatusRequestEntityTooLarge
+	}
+	if stderrors.Is(err, errors.ErrBlockedAddress) {
+		return http.StatusForbidden
+	}
+	var circuitOpen *errors.CircuitOpenError
+	if stderrors.As(err, &circuitOpen) {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusBadRequest
+}
+
+// clientError returns the message a fetch error is reported to the client
+// with. Blocked fetches get the bare error, since the resolved address it
+// names would let anyone probe internal host names; the log keeps it.
+func clientError(err error) string {
+	if stderrors.Is(err, errors.ErrBlockedAddress) {
+		return errors.ErrBlockedAddress.Error()
+	}
+	return err.Error()
+}
+
+// retryAfterSeconds formats the wait until t as a Retry-After header value
+func retryAfterSeconds(t time.Time) string {
+	seconds := int(math.Ceil(time.Until(t).Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	return strconv.Itoa(seconds)
+}
@@ -0,0 +1,83 @@
+package handlers
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"rss-feed-to-csv/internal/config"
+)
+
+// newTestHandler creates a handler allowed to fetch from loopback test
+// servers. configure, if set, adjusts the configuration first.
+func newTestHandler(t *testing.T, configure func(cfg *config.Config)) *Handler {
+	t.Helper()
+	cfg := &config.Config{
+		WriteTimeout:     5 * time.Second,
+		RSSFetchTimeout:  5 * time.Second,
+		MaxRSSSize:       1 << 20,
+		UserAgent:        "test",
+		FetchMaxAttempts: 1,
+		BatchMaxFeeds:    10,
+		BatchConcurrency: 2,
+		BatchTimeout:     5 * time.Second,
+		FeedsMaxKnown:    10,
+		CacheBackend:     "none",
+		MaxURLLength:     2048,
+		AllowedCIDRs:     []string{"127.0.0.0/8", "::1"},
+	}
+	if configure != nil {
+		configure(cfg)
+	}
+	handler, err := NewHandler(cfg)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+	return handler
+}
+
+// newFeedServer serves an RSS feed titled after the path at every path
+// ending in .xml and 404 elsewhere. Paths starting with /slow/ answer
+// after delay, or when the request is cancelled.
+func newFeedServer(t *testing.T, delay time.Duration) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, ".xml") {
+			http.NotFound(w, r)
+			return
+		}
+		if strings.HasPrefix(r.URL.Path, "/slow/") {
+			select {
+			case <-time.After(delay):
+			case <-r.Context().Done():
+				return
+			}
+		}
+		w.Header().Set("Content-Type", "application/rss+xml")
+		fmt.Fprintf(w, `<?xml version="1.0"?><rss version="2.0"><channel><title>Feed %[1]s</title>`+
+			`<link>https://example.com%[1]s</link><item><title>Item of %[1]s</title>`+
+			`<link>https://example.com%[1]s/1</link></item></channel></rss>`, r.URL.Path)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestHandleExport_BlockedAddress(t *testing.T) {
+	feeds := newFeedServer(t, 0)
+	handler := newTestHandler(t, func(cfg *config.Config) { cfg.AllowedCIDRs = nil })
+
+	req := httptest.NewRequest(http.MethodGet, "/export?url="+url.QueryEscape(feeds.URL+"/a.xml"), nil)
+	rec := httptest.NewRecorder()
+	handler.HandleExport(rec, req)
+
+	if rec.Code != http.StatusForbidden {
+		t.Errorf("status = %d, want 403", rec.Code)
+	}
+	if body := strings.TrimSpace(rec.Body.String()); body != "destination address is not allowed" {
+		t.Errorf("body = %q, want the generic blocked message without the address", body)
+	}
+}
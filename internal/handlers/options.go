@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"net/url"
+	"strconv"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/services"
+)
+
+// parseFetchOptions builds the feed parsing options from the query parameters
+func parseFetchOptions(query url.Values) services.ParseOptions {
+	return services.ParseOptions{
+		Lenient:            query.Get("lenient") == "true",
+		ResolveContentURLs: query.Get("resolve_content_urls") == "true",
+	}
+}
+
+// parseFilter parses the filter query parameter. It returns nil when no
+// filter is given.
+func parseFilter(query url.Values) (*services.Filter, error) {
+	expr := query.Get("filter")
+	if expr == "" {
+		return nil, nil
+	}
+	return services.ParseFilter(expr, time.Now())
+}
+
+// parseExportOptions builds export options from the /export query parameters.
+// It runs before the feed is fetched so invalid requests fail fast.
+func parseExportOptions(query url.Values) (services.ExportOptions, error) {
+	opts := services.ExportOptions{
+		SanitizeHTML:        query.Get("sanitize") == "true",
+		IncludeFeedColumns:  query.Get("feed_columns") == "true",
+		MultiValueSeparator: query.Get("separator"),
+		CSV: services.CSVDialect{
+			BOM:         query.Get("bom") == "true",
+			AlwaysQuote: query.Get("quote") == "all",
+		},
+	}
+
+	opts.Mode = services.ExportMode(query.Get("mode"))
+	switch opts.Mode {
+	case "":
+		opts.Mode = services.ModeItems
+	case services.ModeItems, services.ModeChannel:
+	default:
+		return opts, &errors.ValidationError{
+			Field:   "mode",
+			Message: "must be items or channel",
+		}
+	}
+
+	if name := query.Get("delimiter"); name != "" {
+		delimiter, err := services.ParseCSVDelimiter(name)
+		if err != nil {
+			return opts, err
+		}
+		opts.CSV.Delimiter = delimiter
+	}
+
+	switch query.Get("line_ending") {
+	case "", "lf":
+	case "crlf":
+		opts.CSV.CRLF = true
+	default:
+		return opts, &errors.ValidationError{
+			Field:   "line_ending",
+			Message: "must be lf or crlf",
+		}
+	}
+
+	switch query.Get("quote") {
+	case "", "minimal", "all":
+	default:
+		return opts, &errors.ValidationError{
+			Field:   "quote",
+			Message: "must be minimal or all",
+		}
+	}
+
+	protection, err := services.ParseFormulaProtection(query.Get("formula_protection"))
+	if err != nil {
+		return opts, err
+	}
+	opts.FormulaProtection = protection
+
+	dateFormat, err := services.ParseDateFormat(query.Get("date_format"), query.Get("date_layout"))
+	if err != nil {
+		return opts, err
+	}
+	timeZone, err := services.ParseTimeZone(query.Get("timezone"))
+	if err != nil {
+		return opts, err
+	}
+	// Raw dates cannot be converted, so asking for a zone implies RFC 3339
+	if dateFormat == services.DateRaw && query.Get("timezone") != "" && query.Get("date_format") == "" {
+		dateFormat = services.DateRFC3339
+	}
+	opts.DateFormat = dateFormat
+	opts.DateLayout = query.Get("date_layout")
+	opts.TimeZone = timeZone
+
+	if spec := query.Get("columns"); spec != "" {
+		columns, err := services.ParseColumns(spec)
+		if err != nil {
+			return opts, err
+		}
+		opts.Columns = columns
+	}
+
+	if spec := query.Get("sort"); spec != "" {
+		keys, err := services.ParseSort(spec)
+		if err != nil {
+			return opts, err
+		}
+		opts.Sort = keys
+	}
+
+	if value := query.Get("limit"); value != "" {
+		limit, err := strconv.Atoi(value)
+		if err != nil || limit < 1 {
+			return opts, &errors.ValidationError{
+				Field:   "limit",
+				Message: "must be a positive integer",
+			}
+		}
+		opts.Limit = limit
+	}
+
+	if value := query.Get("offset"); value != "" {
+		offset, err := strconv.Atoi(value)
+		if err != nil || offset < 0 {
+			return opts, &errors.ValidationError{
+				Field:   "offset",
+				Message: "must be a non-negative integer",
+			}
+		}
+		opts.Offset = offset
+	}
+
+	return opts, nil
+}
@@ -0,0 +1,195 @@
+package models
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// AtomNamespace is the XML namespace of Atom 1.0 documents
+const AtomNamespace = "http://www.w3.org/2005/Atom"
+
+// AtomFeed represents the root element of an Atom 1.0 feed
+type AtomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Lang      string      `xml:"http://www.w3.org/XML/1998/namespace lang,attr"`
+	Base      string      `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title     AtomText    `xml:"http://www.w3.org/2005/Atom title"`
+	Subtitle  AtomText    `xml:"http://www.w3.org/2005/Atom subtitle"`
+	Links     []AtomLink  `xml:"http://www.w3.org/2005/Atom link"`
+	Updated   string      `xml:"http://www.w3.org/2005/Atom updated"`
+	Generator string      `xml:"http://www.w3.org/2005/Atom generator"`
+	Icon      string      `xml:"http://www.w3.org/2005/Atom icon"`
+	Logo      string      `xml:"http://www.w3.org/2005/Atom logo"`
+	Entries   []AtomEntry `xml:"http://www.w3.org/2005/Atom entry"`
+}
+
+// AtomEntry represents a single entry in an Atom feed
+type AtomEntry struct {
+	Base         string         `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	ID           string         `xml:"http://www.w3.org/2005/Atom id"`
+	Title        AtomText       `xml:"http://www.w3.org/2005/Atom title"`
+	Links        []AtomLink     `xml:"http://www.w3.org/2005/Atom link"`
+	Summary      AtomText       `xml:"http://www.w3.org/2005/Atom summary"`
+	Content      AtomText       `xml:"http://www.w3.org/2005/Atom content"`
+	Published    string         `xml:"http://www.w3.org/2005/Atom published"`
+	Updated      string         `xml:"http://www.w3.org/2005/Atom updated"`
+	Authors      []AtomPerson   `xml:"http://www.w3.org/2005/Atom author"`
+	Categories   []AtomCategory `xml:"http://www.w3.org/2005/Atom category"`
+	Source       AtomSource     `xml:"http://www.w3.org/2005/Atom source"`
+	MediaContent []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaGroup   MediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+}
+
+// AtomText represents an Atom text construct (text, html or xhtml)
+type AtomText struct {
+	Type  string `xml:"type,attr"`
+	Text  string `xml:",chardata"`
+	Inner string `xml:",innerxml"`
+}
+
+// AtomLink represents an Atom link element
+type AtomLink struct {
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
+}
+
+// AtomCategory represents an Atom category element
+type AtomCategory struct {
+	Term   string `xml:"term,attr"`
+	Scheme string `xml:"scheme,attr"`
+	Label  string `xml:"label,attr"`
+}
+
+// AtomSource represents the metadata of the feed an entry was copied from
+type AtomSource struct {
+	Title AtomText   `xml:"http://www.w3.org/2005/Atom title"`
+	Links []AtomLink `xml:"http://www.w3.org/2005/Atom link"`
+}
+
+// AtomPerson represents an Atom author or contributor
+type AtomPerson struct {
+	Name  string `xml:"name"`
+	Email string `xml:"email"`
+}
+
+// MediaGroup represents a media:group element as used by YouTube and others
+type MediaGroup struct {
+	Description string           `xml:"http://search.yahoo.com/mrss/ description"`
+	Contents    []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails  []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+// MediaThumbnail represents a media:thumbnail element
+type MediaThumbnail struct {
+	URL string `xml:"url,attr"`
+}
+
+// String returns the text content of the construct, keeping markup for xhtml
+func (t AtomText) String() string {
+	if t.Type == "xhtml" {
+		return strings.TrimSpace(t.Inner)
+	}
+	return strings.TrimSpace(t.Text)
+}
+
+// ToRSS converts the Atom feed into the shared RSS model
+func (f *AtomFeed) ToRSS() *RSS {
+	rss := &RSS{
+		Channel: Channel{
+			Title:         f.Title.String(),
+			Description:   f.Subtitle.String(),
+			Language:      f.Lang,
+			LastBuildDate: strings.TrimSpace(f.Updated),
+			Generator:     strings.TrimSpace(f.Generator),
+			Base:          f.Base,
+		},
+	}
+	rss.Channel.SetLink(alternateLink(f.Links))
+	rss.Channel.Image.URL = strings.TrimSpace(f.Logo)
+	if rss.Channel.Image.URL == "" {
+		rss.Channel.Image.URL = strings.TrimSpace(f.Icon)
+	}
+
+	rss.Channel.Items = make([]Item, 0, len(f.Entries))
+	for i := range f.Entries {
+		rss.Channel.Items = append(rss.Channel.Items, f.Entries[i].ToItem())
+	}
+	return rss
+}
+
+// ToItem converts the Atom entry into the shared item model
+func (e *AtomEntry) ToItem() Item {
+	item := Item{
+		Title:          e.Title.String(),
+		Link:           e.AlternateLink(),
+		Description:    e.Summary.String(),
+		PubDate:        e.Published,
+		GUID:           GUID{Value: strings.TrimSpace(e.ID), IsPermaLink: "false"},
+		ContentEncoded: e.Content.String(),
+		Base:           e.Base,
+	}
+	if item.PubDate == "" {
+		item.PubDate = e.Updated
+	}
+	if item.Description == "" {
+		item.Description = strings.TrimSpace(e.MediaGroup.Description)
+	}
+
+	names := make([]string, 0, len(e.Authors))
+	for _, author := range e.Authors {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			names = append(names, name)
+		} else if email := strings.TrimSpace(author.Email); email != "" {
+			names = append(names, email)
+		}
+	}
+	item.Author = PlainText(strings.Join(names, ", "))
+
+	item.MediaContent = append(item.MediaContent, e.MediaContent...)
+	item.MediaContent = append(item.MediaContent, e.MediaGroup.Contents...)
+	for _, thumb := range e.MediaGroup.Thumbnails {
+		item.MediaContent = append(item.MediaContent, MediaContent{URL: thumb.URL, Medium: "image"})
+	}
+	for _, link := range e.Links {
+		switch link.Rel {
+		case "enclosure":
+			item.Enclosures = append(item.Enclosures, Enclosure{URL: link.Href, Length: link.Length, Type: link.Type})
+		case "replies":
+			if item.Comments == "" && (link.Type == "" || link.Type == "text/html") {
+				item.Comments = PlainText(link.Href)
+			}
+		}
+	}
+
+	for _, category := range e.Categories {
+		value := category.Term
+		if value == "" {
+			value = category.Label
+		}
+		item.Categories = append(item.Categories, Category{Domain: category.Scheme, Value: value})
+	}
+
+	item.Source = Source{URL: alternateLink(e.Source.Links), Value: e.Source.Title.String()}
+
+	return item
+}
+
+// AlternateLink returns the entry's rel="alternate" link, falling back to the first link
+func (e *AtomEntry) AlternateLink() string {
+	return alternateLink(e.Links)
+}
+
+// alternateLink picks the rel="alternate" link from a list, falling back to the first link
+func alternateLink(links []AtomLink) string {
+	for _, link := range links {
+		if link.Rel == "" || link.Rel == "alternate" {
+			return link.Href
+		}
+	}
+	if len(links) > 0 {
+		return links[0].Href
+	}
+	return ""
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestAtomFeed_ToRSS(t *testing.T) {
+	xmlData := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/" xml:lang="en">
+	<title>Example Feed</title>
+	<subtitle>Feed subtitle</subtitle>
+	<link rel="self" href="https://example.com/feed.atom"/>
+	<link rel="alternate" href="https://example.com/"/>
+	<updated>2024-01-03T10:00:00Z</updated>
+	<generator uri="https://gohugo.io/">Hugo</generator>
+	<logo>https://example.com/logo.png</logo>
+	<entry>
+		<id>urn:uuid:1</id>
+		<title type="html">Atom &amp; Entry</title>
+		<link rel="self" href="https://example.com/entries/1.atom"/>
+		<link rel="alternate" type="text/html" href="https://example.com/entries/1"/>
+		<summary>Entry summary</summary>
+		<content type="xhtml"><div xmlns="http://www.w3.org/1999/xhtml"><p>Body</p></div></content>
+		<updated>2024-01-02T10:00:00Z</updated>
+		<published>2024-01-01T09:00:00Z</published>
+		<author><name>Jane Doe</name></author>
+		<author><email>john@example.com</email></author>
+		<media:content url="https://example.com/image.jpg" medium="image"/>
+		<category term="golang" scheme="https://example.com/tags"/>
+		<link rel="enclosure" type="audio/mpeg" length="1234" href="https://example.com/episode.mp3"/>
+		<link rel="replies" type="text/html" href="https://example.com/entries/1#comments"/>
+	</entry>
+	<entry>
+		<title>Second</title>
+		<link href="https://example.com/entries/2"/>
+		<updated>2024-01-03T10:00:00Z</updated>
+	</entry>
+</feed>`
+
+	var feed AtomFeed
+	if err := xml.Unmarshal([]byte(xmlData), &feed); err != nil {
+		t.Fatalf("Failed to unmarshal Atom: %v", err)
+	}
+
+	rss := feed.ToRSS()
+	channel := rss.Channel
+	if channel.Title != "Example Feed" || channel.Description != "Feed subtitle" {
+		t.Errorf("Channel title/description = %q / %q", channel.Title, channel.Description)
+	}
+	if got := channel.GetLink(); got != "https://example.com/" {
+		t.Errorf("Channel.GetLink() = %q, want %q", got, "https://example.com/")
+	}
+	if channel.Language != "en" {
+		t.Errorf("Channel.Language = %q, want %q", channel.Language, "en")
+	}
+	if channel.LastBuildDate != "2024-01-03T10:00:00Z" {
+		t.Errorf("Channel.LastBuildDate = %q", channel.LastBuildDate)
+	}
+	if channel.Generator != "Hugo" {
+		t.Errorf("Channel.Generator = %q, want %q", channel.Generator, "Hugo")
+	}
+	if channel.Image.URL != "https://example.com/logo.png" {
+		t.Errorf("Channel.Image.URL = %q", channel.Image.URL)
+	}
+
+	if len(rss.Channel.Items) != 2 {
+		t.Fatalf("len(Channel.Items) = %d, want 2", len(rss.Channel.Items))
+	}
+
+	item := rss.Channel.Items[0]
+	if item.Title != "Atom & Entry" {
+		t.Errorf("Item.Title = %q, want %q", item.Title, "Atom & Entry")
+	}
+	if item.Link != "https://example.com/entries/1" {
+		t.Errorf("Item.Link = %q, want %q", item.Link, "https://example.com/entries/1")
+	}
+	if item.Description != "Entry summary" {
+		t.Errorf("Item.Description = %q, want %q", item.Description, "Entry summary")
+	}
+	if item.ContentEncoded != `<div xmlns="http://www.w3.org/1999/xhtml"><p>Body</p></div>` {
+		t.Errorf("Item.ContentEncoded = %q", item.ContentEncoded)
+	}
+	if item.PubDate != "2024-01-01T09:00:00Z" {
+		t.Errorf("Item.PubDate = %q, want published date", item.PubDate)
+	}
+	if item.Author != "Jane Doe, john@example.com" {
+		t.Errorf("Item.Author = %q, want %q", item.Author, "Jane Doe, john@example.com")
+	}
+	if got := item.GetImageURL(); got != "https://example.com/image.jpg" {
+		t.Errorf("GetImageURL() = %q, want %q", got, "https://example.com/image.jpg")
+	}
+	if item.GUID.Value != "urn:uuid:1" || item.GUID.IsPermaLinkValue() {
+		t.Errorf("Item.GUID = %+v, want non-permalink urn:uuid:1", item.GUID)
+	}
+	if len(item.Categories) != 1 || item.Categories[0] != (Category{Domain: "https://example.com/tags", Value: "golang"}) {
+		t.Errorf("Item.Categories = %+v", item.Categories)
+	}
+	if len(item.Enclosures) != 1 || item.Enclosures[0] != (Enclosure{URL: "https://example.com/episode.mp3", Length: "1234", Type: "audio/mpeg"}) {
+		t.Errorf("Item.Enclosures = %+v", item.Enclosures)
+	}
+	if item.Comments != "https://example.com/entries/1#comments" {
+		t.Errorf("Item.Comments = %q", item.Comments)
+	}
+
+	second := rss.Channel.Items[1]
+	if second.Link != "https://example.com/entries/2" {
+		t.Errorf("Item.Link = %q, want %q", second.Link, "https://example.com/entries/2")
+	}
+	if second.PubDate != "2024-01-03T10:00:00Z" {
+		t.Errorf("Item.PubDate = %q, want updated date", second.PubDate)
+	}
+}
+
+func TestAtomEntry_MediaGroup(t *testing.T) {
+	xmlData := `<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+	<entry>
+		<title>Video</title>
+		<link rel="alternate" href="https://www.youtube.com/watch?v=abc"/>
+		<media:group>
+			<media:content url="https://www.youtube.com/v/abc" type="application/x-shockwave-flash"/>
+			<media:thumbnail url="https://i.ytimg.com/vi/abc/hqdefault.jpg"/>
+			<media:description>Video description</media:description>
+		</media:group>
+	</entry>
+</feed>`
+
+	var feed AtomFeed
+	if err := xml.Unmarshal([]byte(xmlData), &feed); err != nil {
+		t.Fatalf("Failed to unmarshal Atom: %v", err)
+	}
+
+	item := feed.ToRSS().Channel.Items[0]
+	if item.Description != "Video description" {
+		t.Errorf("Item.Description = %q, want %q", item.Description, "Video description")
+	}
+	if got := item.GetImageURL(); got != "https://i.ytimg.com/vi/abc/hqdefault.jpg" {
+		t.Errorf("GetImageURL() = %q, want thumbnail URL", got)
+	}
+}
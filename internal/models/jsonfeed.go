@@ -0,0 +1,165 @@
+package models
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// JSONFeedVersionPrefix is the prefix of the version URL every JSON Feed declares
+const JSONFeedVersionPrefix = "https://jsonfeed.org/version/"
+
+// JSONFeed represents a JSON Feed 1.0/1.1 document
+type JSONFeed struct {
+	Version     string         `json:"version"`
+	Title       string         `json:"title"`
+	HomePageURL string         `json:"home_page_url"`
+	Description string         `json:"description"`
+	Language    string         `json:"language"`
+	Icon        string         `json:"icon"`
+	Favicon     string         `json:"favicon"`
+	Items       []JSONFeedItem `json:"items"`
+}
+
+// JSONFeedItem represents a single item in a JSON Feed
+type JSONFeedItem struct {
+	ID            JSONFeedID           `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	Image         string               `json:"image"`
+	BannerImage   string               `json:"banner_image"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Author        *JSONFeedAuthor      `json:"author"`
+	Authors       []JSONFeedAuthor     `json:"authors"`
+	Tags          []string             `json:"tags"`
+	Attachments   []JSONFeedAttachment `json:"attachments"`
+}
+
+// JSONFeedID is an item id. The spec requires a string, but readers must
+// accept other values such as numbers by converting them to strings.
+type JSONFeedID string
+
+// UnmarshalJSON accepts a string, a number or null as an item id
+func (id *JSONFeedID) UnmarshalJSON(data []byte) error {
+	var value any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&value); err != nil {
+		return err
+	}
+	switch value := value.(type) {
+	case string:
+		*id = JSONFeedID(value)
+	case json.Number:
+		*id = JSONFeedID(value.String())
+	case nil:
+		*id = ""
+	default:
+		return fmt.Errorf("invalid JSON Feed item id %s", data)
+	}
+	return nil
+}
+
+// JSONFeedAuthor represents an author object in a JSON Feed
+type JSONFeedAuthor struct {
+	Name string `json:"name"`
+	URL  string `json:"url"`
+}
+
+// JSONFeedAttachment represents an attachment object in a JSON Feed item
+type JSONFeedAttachment struct {
+	URL         string `json:"url"`
+	MimeType    string `json:"mime_type"`
+	Title       string `json:"title"`
+	SizeInBytes int64  `json:"size_in_bytes"`
+}
+
+// IsValid reports whether the document declares a JSON Feed version
+func (f *JSONFeed) IsValid() bool {
+	return strings.HasPrefix(f.Version, JSONFeedVersionPrefix)
+}
+
+// ToRSS converts the JSON Feed into the shared RSS model
+func (f *JSONFeed) ToRSS() *RSS {
+	rss := &RSS{
+		Channel: Channel{
+			Title:       f.Title,
+			Description: f.Description,
+			Language:    f.Language,
+		},
+	}
+	rss.Channel.SetLink(f.HomePageURL)
+	rss.Channel.Image.URL = f.Icon
+	if rss.Channel.Image.URL == "" {
+		rss.Channel.Image.URL = f.Favicon
+	}
+
+	rss.Channel.Items = make([]Item, 0, len(f.Items))
+	for i := range f.Items {
+		rss.Channel.Items = append(rss.Channel.Items, f.Items[i].ToItem())
+	}
+	return rss
+}
+
+// ToItem converts the JSON Feed item into the shared item model
+func (i *JSONFeedItem) ToItem() Item {
+	item := Item{
+		Title:          i.Title,
+		Link:           i.URL,
+		Description:    i.Summary,
+		PubDate:        i.DatePublished,
+		GUID:           GUID{Value: string(i.ID), IsPermaLink: "false"},
+		ContentEncoded: i.ContentHTML,
+	}
+	if item.Link == "" {
+		item.Link = i.ExternalURL
+	}
+	if item.Description == "" {
+		item.Description = i.ContentText
+	}
+	if item.ContentEncoded == "" {
+		item.ContentEncoded = i.ContentText
+	}
+	if item.PubDate == "" {
+		item.PubDate = i.DateModified
+	}
+
+	// JSON Feed 1.1 replaced author with authors; accept both
+	authors := i.Authors
+	if len(authors) == 0 && i.Author != nil {
+		authors = []JSONFeedAuthor{*i.Author}
+	}
+	names := make([]string, 0, len(authors))
+	for _, author := range authors {
+		if name := strings.TrimSpace(author.Name); name != "" {
+			names = append(names, name)
+		}
+	}
+	item.Author = PlainText(strings.Join(names, ", "))
+
+	for _, tag := range i.Tags {
+		item.Categories = append(item.Categories, Category{Value: tag})
+	}
+
+	for _, image := range []string{i.Image, i.BannerImage} {
+		if image != "" {
+			item.MediaContent = append(item.MediaContent, MediaContent{URL: image, Medium: "image"})
+		}
+	}
+	for _, attachment := range i.Attachments {
+		enclosure := Enclosure{URL: attachment.URL, Type: attachment.MimeType}
+		if attachment.SizeInBytes > 0 {
+			enclosure.Length = strconv.FormatInt(attachment.SizeInBytes, 10)
+		}
+		item.Enclosures = append(item.Enclosures, enclosure)
+	}
+
+	return item
+}
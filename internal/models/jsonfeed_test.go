@@ -0,0 +1,148 @@
+package models
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestJSONFeed_ToRSS(t *testing.T) {
+	data := `{
+		"version": "https://jsonfeed.org/version/1.1",
+		"title": "Example JSON Feed",
+		"home_page_url": "https://example.com/",
+		"language": "en-GB",
+		"items": [
+			{
+				"id": "1",
+				"url": "https://example.com/posts/1",
+				"title": "First post",
+				"content_html": "<p>Hello</p>",
+				"summary": "A short summary",
+				"image": "https://example.com/posts/1.png",
+				"date_published": "2024-05-01T08:00:00Z",
+				"authors": [{"name": "Jane Doe"}, {"name": "John Roe"}],
+				"tags": ["go", "feeds"],
+				"attachments": [{"url": "https://example.com/podcast.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 2048}]
+			},
+			{
+				"id": "2",
+				"external_url": "https://elsewhere.example.com/article",
+				"content_text": "Plain text body",
+				"date_modified": "2024-05-02T08:00:00Z",
+				"author": {"name": "Legacy Author"}
+			}
+		]
+	}`
+
+	var feed JSONFeed
+	if err := json.Unmarshal([]byte(data), &feed); err != nil {
+		t.Fatalf("Failed to unmarshal JSON Feed: %v", err)
+	}
+	if !feed.IsValid() {
+		t.Fatal("IsValid() = false, want true")
+	}
+
+	rss := feed.ToRSS()
+	if rss.Channel.Title != "Example JSON Feed" {
+		t.Errorf("Channel.Title = %q, want %q", rss.Channel.Title, "Example JSON Feed")
+	}
+	if got := rss.Channel.GetLink(); got != "https://example.com/" {
+		t.Errorf("Channel.GetLink() = %q, want %q", got, "https://example.com/")
+	}
+	if rss.Channel.Language != "en-GB" {
+		t.Errorf("Channel.Language = %q, want %q", rss.Channel.Language, "en-GB")
+	}
+	if len(rss.Channel.Items) != 2 {
+		t.Fatalf("len(Channel.Items) = %d, want 2", len(rss.Channel.Items))
+	}
+
+	first := rss.Channel.Items[0]
+	if first.Title != "First post" {
+		t.Errorf("Item.Title = %q, want %q", first.Title, "First post")
+	}
+	if first.Link != "https://example.com/posts/1" {
+		t.Errorf("Item.Link = %q, want %q", first.Link, "https://example.com/posts/1")
+	}
+	if first.Description != "A short summary" {
+		t.Errorf("Item.Description = %q, want %q", first.Description, "A short summary")
+	}
+	if first.ContentEncoded != "<p>Hello</p>" {
+		t.Errorf("Item.ContentEncoded = %q, want %q", first.ContentEncoded, "<p>Hello</p>")
+	}
+	if first.PubDate != "2024-05-01T08:00:00Z" {
+		t.Errorf("Item.PubDate = %q, want %q", first.PubDate, "2024-05-01T08:00:00Z")
+	}
+	if first.Author != "Jane Doe, John Roe" {
+		t.Errorf("Item.Author = %q, want %q", first.Author, "Jane Doe, John Roe")
+	}
+	if len(first.Categories) != 2 || first.Categories[1].Value != "feeds" {
+		t.Errorf("Item.Categories = %+v, want go and feeds", first.Categories)
+	}
+	if got := first.GetImageURL(); got != "https://example.com/posts/1.png" {
+		t.Errorf("GetImageURL() = %q, want %q", got, "https://example.com/posts/1.png")
+	}
+
+	if first.GUID.Value != "1" {
+		t.Errorf("Item.GUID = %q, want %q", first.GUID.Value, "1")
+	}
+	if len(first.Enclosures) != 1 || first.Enclosures[0] != (Enclosure{URL: "https://example.com/podcast.mp3", Length: "2048", Type: "audio/mpeg"}) {
+		t.Errorf("Item.Enclosures = %+v", first.Enclosures)
+	}
+
+	second := rss.Channel.Items[1]
+	if second.Link != "https://elsewhere.example.com/article" {
+		t.Errorf("Item.Link = %q, want external_url", second.Link)
+	}
+	if second.Description != "Plain text body" || second.ContentEncoded != "Plain text body" {
+		t.Errorf("Item text fields = %q / %q, want content_text", second.Description, second.ContentEncoded)
+	}
+	if second.PubDate != "2024-05-02T08:00:00Z" {
+		t.Errorf("Item.PubDate = %q, want date_modified", second.PubDate)
+	}
+	if second.Author != "Legacy Author" {
+		t.Errorf("Item.Author = %q, want %q", second.Author, "Legacy Author")
+	}
+}
+
+func TestJSONFeedID_UnmarshalJSON(t *testing.T) {
+	data := `{"version": "https://jsonfeed.org/version/1.1", "items": [
+		{"id": "abc"}, {"id": 123}, {"id": 1.5e3}, {"id": null}, {}
+	]}`
+
+	var feed JSONFeed
+	if err := json.Unmarshal([]byte(data), &feed); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	want := []string{"abc", "123", "1.5e3", "", ""}
+	if len(feed.Items) != len(want) {
+		t.Fatalf("got %d items, want %d", len(feed.Items), len(want))
+	}
+	for i, item := range feed.Items {
+		if guid := item.ToItem().GUID.Value; guid != want[i] {
+			t.Errorf("item %d GUID = %q, want %q", i, guid, want[i])
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"items": [{"id": {"nested": true}}]}`), &feed); err == nil {
+		t.Error("Unmarshal() should reject an object id")
+	}
+}
+
+func TestJSONFeed_IsValid(t *testing.T) {
+	tests := []struct {
+		version string
+		want    bool
+	}{
+		{"https://jsonfeed.org/version/1", true},
+		{"https://jsonfeed.org/version/1.1", true},
+		{"", false},
+		{"2.0", false},
+	}
+
+	for _, tt := range tests {
+		feed := JSONFeed{Version: tt.version}
+		if got := feed.IsValid(); got != tt.want {
+			t.Errorf("IsValid(%q) = %v, want %v", tt.version, got, tt.want)
+		}
+	}
+}
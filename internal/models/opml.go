@@ -0,0 +1,122 @@
+package models
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// OPML represents an OPML document, the format feed readers use to import
+// and export subscription lists
+type OPML struct {
+	XMLName xml.Name  `xml:"opml"`
+	Version string    `xml:"version,attr"`
+	Title   string    `xml:"head>title"`
+	Outline []Outline `xml:"body>outline"`
+}
+
+// Outline represents an OPML outline. Outlines with an xmlUrl are feeds;
+// the others are folders grouping nested outlines.
+type Outline struct {
+	Text     string    `xml:"text,attr"`
+	Title    string    `xml:"title,attr,omitempty"`
+	Type     string    `xml:"type,attr,omitempty"`
+	XMLURL   string    `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL  string    `xml:"htmlUrl,attr,omitempty"`
+	Category string    `xml:"category,attr,omitempty"`
+	Outlines []Outline `xml:"outline"`
+}
+
+// OPMLFeed is a feed listed in an OPML document
+type OPMLFeed struct {
+	URL        string
+	Title      string
+	SiteURL    string
+	Categories []string
+}
+
+// Feeds returns every feed outline, including those nested in folders, in
+// document order. A feed's categories are the names of the folders it is
+// in followed by the entries of its category attribute.
+func (o *OPML) Feeds() []OPMLFeed {
+	var feeds []OPMLFeed
+	var walk func(outlines []Outline, folders []string)
+	walk = func(outlines []Outline, folders []string) {
+		for _, outline := range outlines {
+			url := strings.TrimSpace(outline.XMLURL)
+			if url == "" {
+				nested := append([]string(nil), folders...)
+				walk(outline.Outlines, appendCategory(nested, outline.name()))
+				continue
+			}
+
+			categories := append([]string(nil), folders...)
+			for _, category := range strings.Split(outline.Category, ",") {
+				categories = appendCategory(categories, strings.Trim(strings.TrimSpace(category), "/"))
+			}
+			feeds = append(feeds, OPMLFeed{
+				URL:        url,
+				Title:      outline.name(),
+				SiteURL:    strings.TrimSpace(outline.HTMLURL),
+				Categories: categories,
+			})
+			walk(outline.Outlines, folders)
+		}
+	}
+	walk(o.Outline, nil)
+	return feeds
+}
+
+// NewOPML builds an OPML 2.0 document listing the feeds. Feeds are grouped
+// in folders by their first category; all categories are also kept in the
+// category attribute.
+func NewOPML(title string, feeds []OPMLFeed) *OPML {
+	opml := &OPML{Version: "2.0", Title: title}
+	folders := make(map[string]int)
+	for _, feed := range feeds {
+		outline := Outline{
+			Text:     feed.Title,
+			Title:    feed.Title,
+			Type:     "rss",
+			XMLURL:   feed.URL,
+			HTMLURL:  feed.SiteURL,
+			Category: strings.Join(feed.Categories, ","),
+		}
+		if outline.Text == "" {
+			outline.Text = feed.URL
+		}
+
+		if len(feed.Categories) == 0 {
+			opml.Outline = append(opml.Outline, outline)
+			continue
+		}
+		folder, ok := folders[feed.Categories[0]]
+		if !ok {
+			folder = len(opml.Outline)
+			folders[feed.Categories[0]] = folder
+			opml.Outline = append(opml.Outline, Outline{Text: feed.Categories[0], Title: feed.Categories[0]})
+		}
+		opml.Outline[folder].Outlines = append(opml.Outline[folder].Outlines, outline)
+	}
+	return opml
+}
+
+// name returns the outline's title, falling back to its text
+func (o Outline) name() string {
+	if title := strings.TrimSpace(o.Title); title != "" {
+		return title
+	}
+	return strings.TrimSpace(o.Text)
+}
+
+// appendCategory appends a category unless it is empty or already present
+func appendCategory(categories []string, category string) []string {
+	if category == "" {
+		return categories
+	}
+	for _, existing := range categories {
+		if existing == category {
+			return categories
+		}
+	}
+	return append(categories, category)
+}
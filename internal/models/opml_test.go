@@ -0,0 +1,84 @@
+package models
+
+import (
+	"encoding/xml"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestOPML_Feeds(t *testing.T) {
+	data := `<?xml version="1.0"?>
+	<opml version="2.0">
+		<head><title>Subscriptions</title></head>
+		<body>
+			<outline text="Top" type="rss" xmlUrl="https://example.com/top.xml" htmlUrl="https://example.com/"/>
+			<outline text="News">
+				<outline text="A" title="Feed A" type="rss" xmlUrl=" https://a.example/feed " category="/Daily,Tech"/>
+				<outline text="Deeper">
+					<outline text="B" type="rss" xmlUrl="https://b.example/rss" category="News"/>
+				</outline>
+			</outline>
+			<outline text="Sports"><outline text="C" xmlUrl="https://c.example/rss"/></outline>
+			<outline text="Just a note"/>
+		</body>
+	</opml>`
+
+	var opml OPML
+	if err := xml.Unmarshal([]byte(data), &opml); err != nil {
+		t.Fatalf("Failed to unmarshal OPML: %v", err)
+	}
+	if opml.Title != "Subscriptions" {
+		t.Errorf("Title = %q, want %q", opml.Title, "Subscriptions")
+	}
+
+	want := []OPMLFeed{
+		{URL: "https://example.com/top.xml", Title: "Top", SiteURL: "https://example.com/", Categories: []string{}},
+		{URL: "https://a.example/feed", Title: "Feed A", Categories: []string{"News", "Daily", "Tech"}},
+		{URL: "https://b.example/rss", Title: "B", Categories: []string{"News", "Deeper"}},
+		{URL: "https://c.example/rss", Title: "C", Categories: []string{"Sports"}},
+	}
+	got := opml.Feeds()
+	if len(got) != len(want) {
+		t.Fatalf("Feeds() = %+v, want %+v", got, want)
+	}
+	for i := range want {
+		// Printing treats nil and empty category lists alike
+		if fmt.Sprint(got[i]) != fmt.Sprint(want[i]) {
+			t.Errorf("Feeds()[%d] = %+v, want %+v", i, got[i], want[i])
+		}
+	}
+}
+
+func TestNewOPML(t *testing.T) {
+	feeds := []OPMLFeed{
+		{URL: "https://a.example/feed", Title: "A", Categories: []string{"News", "Daily"}},
+		{URL: "https://b.example/feed"},
+		{URL: "https://c.example/feed", Title: "C", Categories: []string{"News"}},
+	}
+
+	data, err := xml.Marshal(NewOPML("Feeds", feeds))
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+
+	var opml OPML
+	if err := xml.Unmarshal(data, &opml); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if opml.Version != "2.0" || opml.Title != "Feeds" {
+		t.Errorf("version/title = %q/%q, want 2.0/Feeds", opml.Version, opml.Title)
+	}
+	if len(opml.Outline) != 2 || opml.Outline[0].Text != "News" || len(opml.Outline[0].Outlines) != 2 {
+		t.Fatalf("outlines = %+v, want a News folder with two feeds and one top-level feed", opml.Outline)
+	}
+	if b := opml.Outline[1]; b.XMLURL != "https://b.example/feed" || b.Text != "https://b.example/feed" {
+		t.Errorf("untitled feed outline = %+v", b)
+	}
+
+	// The export reads back as the same feeds
+	got := opml.Feeds()
+	if len(got) != 3 || got[0].URL != "https://a.example/feed" || !reflect.DeepEqual(got[0].Categories, []string{"News", "Daily"}) {
+		t.Errorf("round trip = %+v", got)
+	}
+}
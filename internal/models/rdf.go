@@ -0,0 +1,101 @@
+package models
+
+import (
+	"encoding/xml"
+	"strings"
+)
+
+// RDFNamespace is the XML namespace of the rdf:RDF root used by RSS 1.0
+const RDFNamespace = "http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+
+// RDF represents the root element of an RSS 1.0 (RDF Site Summary) feed
+type RDF struct {
+	XMLName xml.Name   `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# RDF"`
+	Base    string     `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Channel RDFChannel `xml:"http://purl.org/rss/1.0/ channel"`
+	Image   RDFImage   `xml:"http://purl.org/rss/1.0/ image"`
+	Items   []RDFItem  `xml:"http://purl.org/rss/1.0/ item"`
+}
+
+// RDFChannel represents the RSS 1.0 channel metadata
+type RDFChannel struct {
+	Title       string `xml:"http://purl.org/rss/1.0/ title"`
+	Link        string `xml:"http://purl.org/rss/1.0/ link"`
+	Description string `xml:"http://purl.org/rss/1.0/ description"`
+	Language    string `xml:"http://purl.org/dc/elements/1.1/ language"`
+	Date        string `xml:"http://purl.org/dc/elements/1.1/ date"`
+}
+
+// RDFImage represents the RSS 1.0 image element, a sibling of the channel
+type RDFImage struct {
+	URL   string `xml:"http://purl.org/rss/1.0/ url"`
+	Title string `xml:"http://purl.org/rss/1.0/ title"`
+	Link  string `xml:"http://purl.org/rss/1.0/ link"`
+}
+
+// RDFItem represents a single RSS 1.0 item, including Dublin Core metadata
+type RDFItem struct {
+	About          string         `xml:"http://www.w3.org/1999/02/22-rdf-syntax-ns# about,attr"`
+	Base           string         `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	Title          string         `xml:"http://purl.org/rss/1.0/ title"`
+	Link           string         `xml:"http://purl.org/rss/1.0/ link"`
+	Description    string         `xml:"http://purl.org/rss/1.0/ description"`
+	ContentEncoded string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	Date           string         `xml:"http://purl.org/dc/elements/1.1/ date"`
+	Creators       []string       `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Subjects       []string       `xml:"http://purl.org/dc/elements/1.1/ subject"`
+	MediaContent   []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+}
+
+// ToRSS converts the RDF feed into the shared RSS model
+func (f *RDF) ToRSS() *RSS {
+	rss := &RSS{
+		Channel: Channel{
+			Title:         strings.TrimSpace(f.Channel.Title),
+			Description:   strings.TrimSpace(f.Channel.Description),
+			Language:      strings.TrimSpace(f.Channel.Language),
+			LastBuildDate: strings.TrimSpace(f.Channel.Date),
+			Base:          f.Base,
+			Image: ChannelImage{
+				URL:   strings.TrimSpace(f.Image.URL),
+				Title: strings.TrimSpace(f.Image.Title),
+				Link:  strings.TrimSpace(f.Image.Link),
+			},
+		},
+	}
+	rss.Channel.SetLink(strings.TrimSpace(f.Channel.Link))
+
+	rss.Channel.Items = make([]Item, 0, len(f.Items))
+	for i := range f.Items {
+		rss.Channel.Items = append(rss.Channel.Items, f.Items[i].ToItem())
+	}
+	return rss
+}
+
+// ToItem converts the RDF item into the shared item model
+func (i *RDFItem) ToItem() Item {
+	item := Item{
+		Title:          strings.TrimSpace(i.Title),
+		Link:           strings.TrimSpace(i.Link),
+		Description:    strings.TrimSpace(i.Description),
+		PubDate:        strings.TrimSpace(i.Date),
+		ContentEncoded: i.ContentEncoded,
+		GUID:           GUID{Value: strings.TrimSpace(i.About)},
+		MediaContent:   i.MediaContent,
+		Base:           i.Base,
+	}
+
+	for _, creator := range i.Creators {
+		if creator = strings.TrimSpace(creator); creator != "" {
+			item.Creators = append(item.Creators, creator)
+		}
+	}
+
+	for _, subject := range i.Subjects {
+		if subject = strings.TrimSpace(subject); subject != "" {
+			item.Categories = append(item.Categories, Category{Value: subject})
+		}
+	}
+
+	return item
+}
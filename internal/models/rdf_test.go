@@ -0,0 +1,77 @@
+package models
+
+import (
+	"encoding/xml"
+	"testing"
+)
+
+func TestRDF_ToRSS(t *testing.T) {
+	xmlData := `<?xml version="1.0" encoding="UTF-8"?>
+<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#"
+	xmlns="http://purl.org/rss/1.0/"
+	xmlns:dc="http://purl.org/dc/elements/1.1/">
+	<channel rdf:about="https://example.com/">
+		<title>Example Journal</title>
+		<link>https://example.com/</link>
+		<description>Monthly journal</description>
+		<dc:language>en</dc:language>
+		<items>
+			<rdf:Seq>
+				<rdf:li rdf:resource="https://example.com/articles/1"/>
+			</rdf:Seq>
+		</items>
+	</channel>
+	<item rdf:about="https://example.com/articles/1">
+		<title>Article One</title>
+		<link>https://example.com/articles/1</link>
+		<description>Abstract text</description>
+		<dc:date>2024-03-01T12:00:00+01:00</dc:date>
+		<dc:creator>Ada Lovelace</dc:creator>
+		<dc:creator>Charles Babbage</dc:creator>
+		<dc:subject>Mathematics</dc:subject>
+		<dc:subject>Computing</dc:subject>
+	</item>
+</rdf:RDF>`
+
+	var feed RDF
+	if err := xml.Unmarshal([]byte(xmlData), &feed); err != nil {
+		t.Fatalf("Failed to unmarshal RDF: %v", err)
+	}
+
+	rss := feed.ToRSS()
+	if rss.Channel.Title != "Example Journal" || rss.Channel.Description != "Monthly journal" {
+		t.Errorf("Channel title/description = %q / %q", rss.Channel.Title, rss.Channel.Description)
+	}
+	if got := rss.Channel.GetLink(); got != "https://example.com/" {
+		t.Errorf("Channel.GetLink() = %q, want %q", got, "https://example.com/")
+	}
+	if rss.Channel.Language != "en" {
+		t.Errorf("Channel.Language = %q, want %q", rss.Channel.Language, "en")
+	}
+	if len(rss.Channel.Items) != 1 {
+		t.Fatalf("len(Channel.Items) = %d, want 1", len(rss.Channel.Items))
+	}
+
+	item := rss.Channel.Items[0]
+	if item.Title != "Article One" {
+		t.Errorf("Item.Title = %q, want %q", item.Title, "Article One")
+	}
+	if item.Link != "https://example.com/articles/1" {
+		t.Errorf("Item.Link = %q, want %q", item.Link, "https://example.com/articles/1")
+	}
+	if item.Description != "Abstract text" {
+		t.Errorf("Item.Description = %q, want %q", item.Description, "Abstract text")
+	}
+	if item.PubDate != "2024-03-01T12:00:00+01:00" {
+		t.Errorf("Item.PubDate = %q, want dc:date value", item.PubDate)
+	}
+	if len(item.Creators) != 2 || item.Creators[0] != "Ada Lovelace" || item.Creators[1] != "Charles Babbage" {
+		t.Errorf("Item.Creators = %q, want Ada Lovelace and Charles Babbage", item.Creators)
+	}
+	if item.GUID.Value != "https://example.com/articles/1" {
+		t.Errorf("Item.GUID = %q, want rdf:about value", item.GUID.Value)
+	}
+	if len(item.Categories) != 2 || item.Categories[0].Value != "Mathematics" || item.Categories[1].Value != "Computing" {
+		t.Errorf("Item.Categories = %+v, want Mathematics and Computing", item.Categories)
+	}
+}
@@ -1,26 +1,138 @@
 package models
 
-import "encoding/xml"
+import (
+	"encoding/xml"
+	"strings"
+	"time"
+)
 
 // RSS represents the root RSS feed structure
 type RSS struct {
 	XMLName xml.Name `xml:"rss"`
+	Base    string   `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
 	Channel Channel  `xml:"channel"`
 }
 
-// Channel represents the RSS channel containing items
+// Channel represents the RSS channel metadata and its items
 type Channel struct {
-	Items []Item `xml:"item"`
+	Title         string        `xml:"title"`
+	Links         []ChannelLink `xml:"link"`
+	Description   string        `xml:"description"`
+	Language      string        `xml:"language"`
+	LastBuildDate string        `xml:"lastBuildDate"`
+	Image         ChannelImage  `xml:"image"`
+	Generator     string        `xml:"generator"`
+	Items         []Item        `xml:"item"`
+	// Base is the channel's xml:base, which relative URLs are resolved against
+	Base string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	// LastBuildAt is LastBuildDate parsed, or zero if it could not be parsed
+	LastBuildAt time.Time `xml:"-"`
+}
+
+// ChannelLink represents a link element of a channel. RSS 2.0 feeds commonly
+// mix the plain <link> with <atom:link rel="self">, so both are collected.
+type ChannelLink struct {
+	XMLName xml.Name
+	Href    string `xml:"href,attr"`
+	Rel     string `xml:"rel,attr"`
+	Value   string `xml:",chardata"`
+}
+
+// ChannelImage represents the image associated with a channel
+type ChannelImage struct {
+	URL   string `xml:"url"`
+	Title string `xml:"title"`
+	Link  string `xml:"link"`
 }
 
 // Item represents a single RSS feed item
 type Item struct {
-	Title          string         `xml:"title"`
-	Link           string         `xml:"link"`
-	Description    string         `xml:"description"`
-	PubDate        string         `xml:"pubDate"`
-	ContentEncoded string         `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
-	MediaContent   []MediaContent `xml:"http://search.yahoo.com/mrss/ content"`
+	Title          string          `xml:"title"`
+	Link           string          `xml:"link"`
+	Description    string          `xml:"description"`
+	PubDate        string          `xml:"pubDate"`
+	GUID           GUID            `xml:"guid"`
+	Author         PlainText       `xml:"author"`
+	Creators       []string        `xml:"http://purl.org/dc/elements/1.1/ creator"`
+	Categories     PlainCategories `xml:"category"`
+	Comments       PlainText       `xml:"comments"`
+	Enclosures     []Enclosure     `xml:"enclosure"`
+	Source         Source          `xml:"source"`
+	ContentEncoded string          `xml:"http://purl.org/rss/1.0/modules/content/ encoded"`
+	MediaContent   []MediaContent  `xml:"http://search.yahoo.com/mrss/ content"`
+	// Base is the item's xml:base, which relative URLs are resolved against
+	Base string `xml:"http://www.w3.org/XML/1998/namespace base,attr"`
+	// PublishedAt is PubDate parsed, or zero if it could not be parsed
+	PublishedAt time.Time `xml:"-"`
+	// FeedURL and FeedTitle identify the feed the item was fetched from,
+	// which tells items apart once several feeds are merged
+	FeedURL   string `xml:"-"`
+	FeedTitle string `xml:"-"`
+}
+
+// GUID represents the unique identifier of an RSS item
+type GUID struct {
+	IsPermaLink string `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+// Category represents a category assigned to an RSS item
+type Category struct {
+	Domain string `xml:"domain,attr"`
+	Value  string `xml:",chardata"`
+}
+
+// Enclosure represents a media object attached to an RSS item
+type Enclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// Source represents the RSS channel an item was republished from
+type Source struct {
+	URL   string `xml:"url,attr"`
+	Value string `xml:",chardata"`
+}
+
+// PlainText holds the text of an element without a namespace. Extension
+// elements sharing the same local name, such as slash:comments next to
+// <comments> or itunes:author next to <author>, are ignored instead of
+// overwriting the value.
+type PlainText string
+
+// UnmarshalXML implements xml.Unmarshaler
+func (t *PlainText) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var value string
+	if err := d.DecodeElement(&value, &start); err != nil {
+		return err
+	}
+	if start.Name.Space == "" {
+		*t = PlainText(strings.TrimSpace(value))
+	}
+	return nil
+}
+
+// PlainCategories holds the category elements without a namespace, leaving
+// out extension elements such as media:category and itunes:category
+type PlainCategories []Category
+
+// UnmarshalXML implements xml.Unmarshaler. It is called once per element.
+func (c *PlainCategories) UnmarshalXML(d *xml.Decoder, start xml.StartElement) error {
+	var category Category
+	if err := d.DecodeElement(&category, &start); err != nil {
+		return err
+	}
+	if start.Name.Space == "" {
+		*c = append(*c, category)
+	}
+	return nil
+}
+
+// IsPermaLinkValue reports whether the GUID is a permalink. Per RSS 2.0 a
+// GUID without the isPermaLink attribute is a permalink.
+func (g GUID) IsPermaLinkValue() bool {
+	return g.IsPermaLink == "" || strings.EqualFold(strings.TrimSpace(g.IsPermaLink), "true")
 }
 
 // MediaContent represents media content in RSS items
@@ -30,13 +142,45 @@ type MediaContent struct {
 	Medium string `xml:"medium,attr"`
 }
 
-// GetImageURL returns the first image URL found in the media content
+// GetLink returns the channel's website link, ignoring atom:link self references
+func (c *Channel) GetLink() string {
+	for _, link := range c.Links {
+		if link.XMLName.Space == "" {
+			if value := strings.TrimSpace(link.Value); value != "" {
+				return value
+			}
+		}
+	}
+	for _, link := range c.Links {
+		if link.Href != "" && (link.Rel == "" || link.Rel == "alternate") {
+			return link.Href
+		}
+	}
+	return ""
+}
+
+// SetLink replaces the channel links with a single website link
+func (c *Channel) SetLink(link string) {
+	if link == "" {
+		c.Links = nil
+		return
+	}
+	c.Links = []ChannelLink{{Value: link}}
+}
+
+// GetImageURL returns the first image URL found in the media content,
+// falling back to the first image enclosure
 func (item *Item) GetImageURL() string {
 	for _, media := range item.MediaContent {
 		if media.IsImage() {
 			return media.URL
 		}
 	}
+	for _, enclosure := range item.Enclosures {
+		if strings.HasPrefix(enclosure.Type, "image/") {
+			return enclosure.URL
+		}
+	}
 	return ""
 }
 
@@ -49,4 +193,4 @@ func (mc *MediaContent) IsImage() bool {
 		return true
 	}
 	return false
-}
\ No newline at end of file
+}
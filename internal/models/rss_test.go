@@ -45,6 +45,16 @@ func TestItem_GetImageURL(t *testing.T) {
 			item: Item{},
 			want: "",
 		},
+		{
+			name: "image enclosure fallback",
+			item: Item{
+				Enclosures: []Enclosure{
+					{URL: "https://example.com/audio.mp3", Type: "audio/mpeg"},
+					{URL: "https://example.com/cover.jpg", Type: "image/jpeg"},
+				},
+			},
+			want: "https://example.com/cover.jpg",
+		},
 		{
 			name: "media without image type",
 			item: Item{
@@ -174,4 +184,193 @@ func TestRSSWithMediaContent(t *testing.T) {
 	if item.MediaContent[0].URL != "https://example.com/media.jpg" {
 		t.Errorf("MediaContent[0].URL = %q, want %q", item.MediaContent[0].URL, "https://example.com/media.jpg")
 	}
-}
\ No newline at end of file
+}
+
+func TestRSSChannelMetadata(t *testing.T) {
+	xmlData := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:atom="http://www.w3.org/2005/Atom">
+	<channel>
+		<title>Example Feed</title>
+		<atom:link href="https://example.com/feed/" rel="self" type="application/rss+xml"/>
+		<link>https://example.com/</link>
+		<description>All the news</description>
+		<language>en-us</language>
+		<lastBuildDate>Mon, 02 Jan 2006 15:04:05 GMT</lastBuildDate>
+		<generator>WordPress 6.4</generator>
+		<image>
+			<url>https://example.com/logo.png</url>
+			<title>Example Feed</title>
+			<link>https://example.com/</link>
+		</image>
+		<item><title>Test Item</title></item>
+	</channel>
+</rss>`
+
+	var rss RSS
+	if err := xml.Unmarshal([]byte(xmlData), &rss); err != nil {
+		t.Fatalf("Failed to unmarshal RSS: %v", err)
+	}
+
+	channel := rss.Channel
+	if channel.Title != "Example Feed" {
+		t.Errorf("Channel.Title = %q, want %q", channel.Title, "Example Feed")
+	}
+	if got := channel.GetLink(); got != "https://example.com/" {
+		t.Errorf("Channel.GetLink() = %q, want %q", got, "https://example.com/")
+	}
+	if channel.Description != "All the news" {
+		t.Errorf("Channel.Description = %q, want %q", channel.Description, "All the news")
+	}
+	if channel.Language != "en-us" {
+		t.Errorf("Channel.Language = %q, want %q", channel.Language, "en-us")
+	}
+	if channel.LastBuildDate != "Mon, 02 Jan 2006 15:04:05 GMT" {
+		t.Errorf("Channel.LastBuildDate = %q", channel.LastBuildDate)
+	}
+	if channel.Generator != "WordPress 6.4" {
+		t.Errorf("Channel.Generator = %q, want %q", channel.Generator, "WordPress 6.4")
+	}
+	if channel.Image.URL != "https://example.com/logo.png" {
+		t.Errorf("Channel.Image.URL = %q, want %q", channel.Image.URL, "https://example.com/logo.png")
+	}
+}
+
+func TestChannel_GetLink(t *testing.T) {
+	tests := []struct {
+		name    string
+		channel Channel
+		want    string
+	}{
+		{
+			name:    "plain link",
+			channel: Channel{Links: []ChannelLink{{Value: " https://example.com/ "}}},
+			want:    "https://example.com/",
+		},
+		{
+			name: "atom self link only",
+			channel: Channel{Links: []ChannelLink{
+				{XMLName: xml.Name{Space: "http://www.w3.org/2005/Atom", Local: "link"}, Href: "https://example.com/feed", Rel: "self"},
+			}},
+			want: "",
+		},
+		{
+			name: "atom alternate link fallback",
+			channel: Channel{Links: []ChannelLink{
+				{XMLName: xml.Name{Space: "http://www.w3.org/2005/Atom", Local: "link"}, Href: "https://example.com/", Rel: "alternate"},
+			}},
+			want: "https://example.com/",
+		},
+		{
+			name:    "no links",
+			channel: Channel{},
+			want:    "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.channel.GetLink(); got != tt.want {
+				t.Errorf("GetLink() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRSSItemFields(t *testing.T) {
+	xmlData := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/" xmlns:slash="http://purl.org/rss/1.0/modules/slash/">
+	<channel>
+		<item>
+			<title>Test Item</title>
+			<guid isPermaLink="false">tag:example.com,2024:1</guid>
+			<author>editor@example.com (Editor)</author>
+			<dc:creator>Jane Doe</dc:creator>
+			<category domain="https://example.com/sections">News</category>
+			<category>Politics</category>
+			<comments>https://example.com/item1#comments</comments>
+			<slash:comments>12</slash:comments>
+			<enclosure url="https://example.com/audio.mp3" length="12345" type="audio/mpeg"/>
+			<source url="https://other.example.com/rss">Other Feed</source>
+		</item>
+	</channel>
+</rss>`
+
+	var rss RSS
+	if err := xml.Unmarshal([]byte(xmlData), &rss); err != nil {
+		t.Fatalf("Failed to unmarshal RSS: %v", err)
+	}
+
+	item := rss.Channel.Items[0]
+	if item.GUID.Value != "tag:example.com,2024:1" || item.GUID.IsPermaLinkValue() {
+		t.Errorf("Item.GUID = %+v, want non-permalink tag GUID", item.GUID)
+	}
+	if item.Author != "editor@example.com (Editor)" {
+		t.Errorf("Item.Author = %q", item.Author)
+	}
+	if len(item.Creators) != 1 || item.Creators[0] != "Jane Doe" {
+		t.Errorf("Item.Creators = %q, want [Jane Doe]", item.Creators)
+	}
+	if len(item.Categories) != 2 {
+		t.Fatalf("len(Item.Categories) = %d, want 2", len(item.Categories))
+	}
+	if item.Categories[0].Domain != "https://example.com/sections" || item.Categories[0].Value != "News" {
+		t.Errorf("Item.Categories[0] = %+v", item.Categories[0])
+	}
+	if item.Comments != "https://example.com/item1#comments" {
+		t.Errorf("Item.Comments = %q, slash:comments should not override it", item.Comments)
+	}
+	if len(item.Enclosures) != 1 || item.Enclosures[0].Length != "12345" || item.Enclosures[0].Type != "audio/mpeg" {
+		t.Errorf("Item.Enclosures = %+v", item.Enclosures)
+	}
+	if item.Source.URL != "https://other.example.com/rss" || item.Source.Value != "Other Feed" {
+		t.Errorf("Item.Source = %+v", item.Source)
+	}
+}
+
+func TestRSSItemFields_ExtensionElements(t *testing.T) {
+	xmlData := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:media="http://search.yahoo.com/mrss/">
+	<channel>
+		<item>
+			<title>Episode 1</title>
+			<author>host@example.com (Host)</author>
+			<itunes:author>Podcast Co</itunes:author>
+			<itunes:category text="Technology"/>
+			<media:category scheme="urn:iab:categories">IAB19</media:category>
+			<category>Tech</category>
+		</item>
+	</channel>
+</rss>`
+
+	var rss RSS
+	if err := xml.Unmarshal([]byte(xmlData), &rss); err != nil {
+		t.Fatalf("Failed to unmarshal RSS: %v", err)
+	}
+
+	item := rss.Channel.Items[0]
+	if item.Author != "host@example.com (Host)" {
+		t.Errorf("Item.Author = %q, itunes:author should not override it", item.Author)
+	}
+	if len(item.Categories) != 1 || item.Categories[0].Value != "Tech" {
+		t.Errorf("Item.Categories = %+v, want only the plain category", item.Categories)
+	}
+}
+
+func TestGUID_IsPermaLinkValue(t *testing.T) {
+	tests := []struct {
+		attr string
+		want bool
+	}{
+		{"", true},
+		{"true", true},
+		{"TRUE", true},
+		{"false", false},
+	}
+
+	for _, tt := range tests {
+		guid := GUID{IsPermaLink: tt.attr, Value: "https://example.com/1"}
+		if got := guid.IsPermaLinkValue(); got != tt.want {
+			t.Errorf("IsPermaLinkValue(%q) = %v, want %v", tt.attr, got, tt.want)
+		}
+	}
+}
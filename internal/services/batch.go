@@ -0,0 +1,84 @@
+package services
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"rss-feed-to-csv/internal/models"
+)
+
+// FeedReport is the outcome of one feed of a batch export
+type FeedReport struct {
+	// URL is the requested feed URL
+	URL string
+	// FeedURL is the feed that was exported, which differs from URL when
+	// the feed was discovered from an HTML page
+	FeedURL string
+	// Title is the feed's title
+	Title string
+	// Items is the number of items the feed contributed
+	Items int
+	// Err is why the feed was left out of the export, or nil
+	Err error
+}
+
+// summaryHeaders label the columns of the per-feed summary that closes a batch export
+var summaryHeaders = []string{"FeedURL", "FeedTitle", "Status", "Items", "Error"}
+
+// record returns the summary row describing the feed
+func (r FeedReport) record() []string {
+	if r.Err != nil {
+		return []string{r.URL, r.Title, "error", "0", r.Err.Error()}
+	}
+	url := r.FeedURL
+	if url == "" {
+		url = r.URL
+	}
+	return []string{url, r.Title, "ok", strconv.Itoa(r.Items), ""}
+}
+
+// FetchBatch fetches several feeds, at most concurrency at a time, and merges
+// their items into one feed in the order the URLs were given. A feed that
+// fails is reported and left out instead of failing the whole batch.
+func (f *RSSFetcher) FetchBatch(ctx context.Context, urls []string, concurrency int, opts ParseOptions) (*models.RSS, []FeedReport) {
+	results := make([]*FetchResult, len(urls))
+	reports := make([]FeedReport, len(urls))
+
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	if concurrency > len(urls) {
+		concurrency = len(urls)
+	}
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range concurrency {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				reports[i].URL = urls[i]
+				results[i], reports[i].Err = f.FetchFeed(ctx, urls[i], opts)
+			}
+		}()
+	}
+	for i := range urls {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	merged := &models.RSS{}
+	for i, result := range results {
+		if result == nil {
+			continue
+		}
+		reports[i].FeedURL = result.FeedURL
+		reports[i].Title = result.RSS.Channel.Title
+		reports[i].Items = len(result.RSS.Channel.Items)
+		merged.Channel.Items = append(merged.Channel.Items, result.RSS.Channel.Items...)
+	}
+	return merged, reports
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"rss-feed-to-csv/internal/models"
+)
+
+func TestRSSFetcher_FetchBatch(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			peak := maxInFlight.Load()
+			if n <= peak || maxInFlight.CompareAndSwap(peak, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+
+		if r.URL.Path == "/missing" {
+			http.NotFound(w, r)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/")
+		fmt.Fprintf(w, `<rss version="2.0"><channel><title>Feed %s</title><item><title>%s-1</title></item><item><title>%s-2</title></item></channel></rss>`, name, name, name)
+	}))
+	defer server.Close()
+
+	urls := []string{server.URL + "/a", server.URL + "/missing", server.URL + "/b", server.URL + "/c", server.URL + "/d"}
+	fetcher := NewRSSFetcher(FetcherOptions{Timeout: 5 * time.Second, UserAgent: "test-agent"})
+	rss, reports := fetcher.FetchBatch(context.Background(), urls, 2, ParseOptions{})
+
+	if peak := maxInFlight.Load(); peak > 2 {
+		t.Errorf("peak concurrent fetches = %d, want at most 2", peak)
+	}
+
+	var titles []string
+	for _, item := range rss.Channel.Items {
+		titles = append(titles, item.Title)
+	}
+	if got, want := strings.Join(titles, ","), "a-1,a-2,b-1,b-2,c-1,c-2,d-1,d-2"; got != want {
+		t.Errorf("merged items = %s, want %s", got, want)
+	}
+	if item := rss.Channel.Items[2]; item.FeedURL != urls[2] || item.FeedTitle != "Feed b" {
+		t.Errorf("item feed = %q/%q, want %q/%q", item.FeedURL, item.FeedTitle, urls[2], "Feed b")
+	}
+
+	if len(reports) != len(urls) {
+		t.Fatalf("len(reports) = %d, want %d", len(reports), len(urls))
+	}
+	for i, report := range reports {
+		if report.URL != urls[i] {
+			t.Errorf("reports[%d].URL = %q, want %q", i, report.URL, urls[i])
+		}
+		if failed := i == 1; (report.Err != nil) != failed {
+			t.Errorf("reports[%d].Err = %v, want failure %v", i, report.Err, failed)
+		}
+	}
+	if reports[0].Items != 2 || reports[0].Title != "Feed a" {
+		t.Errorf("reports[0] = %+v, want 2 items from Feed a", reports[0])
+	}
+}
+
+// batchFeed returns a merged feed and its summary as a batch export would see them
+func batchFeed() (*models.RSS, ExportOptions) {
+	rss := &models.RSS{Channel: models.Channel{Items: []models.Item{
+		{Title: "One", FeedURL: "https://a.example/feed", FeedTitle: "A"},
+		{Title: "Two", FeedURL: "https://b.example/feed", FeedTitle: "B"},
+	}}}
+	columns, _ := ParseColumns("title")
+	opts := ExportOptions{
+		Columns:      columns,
+		BatchColumns: true,
+		Summary: []FeedReport{
+			{URL: "https://a.example/feed", FeedURL: "https://a.example/feed", Title: "A", Items: 1},
+			{URL: "https://b.example/", FeedURL: "https://b.example/feed", Title: "B", Items: 1},
+			{URL: "https://c.example/feed", Err: errors.New("-boom")},
+		},
+	}
+	return rss, opts
+}
+
+func TestCSVExporter_Export_BatchSummary(t *testing.T) {
+	rss, opts := batchFeed()
+
+	var buf bytes.Buffer
+	if err := NewCSVExporter().Export(context.Background(), &buf, rss, opts); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	reader := csv.NewReader(&buf)
+	reader.FieldsPerRecord = -1
+	records, err := reader.ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+
+	want := [][]string{
+		{"FeedURL", "FeedTitle", "Title"},
+		{"https://a.example/feed", "A", "One"},
+		{"https://b.example/feed", "B", "Two"},
+		{"FeedURL", "FeedTitle", "Status", "Items", "Error"},
+		{"https://a.example/feed", "A", "ok", "1", ""},
+		{"https://b.example/feed", "B", "ok", "1", ""},
+		{"https://c.example/feed", "", "error", "0", "'-boom"},
+	}
+	if len(records) != len(want) {
+		t.Fatalf("records = %q, want %q", records, want)
+	}
+	for i := range want {
+		if strings.Join(records[i], ",") != strings.Join(want[i], ",") {
+			t.Errorf("record %d = %q, want %q", i, records[i], want[i])
+		}
+	}
+}
+
+func TestJSONExporter_Export_BatchSummary(t *testing.T) {
+	rss, opts := batchFeed()
+
+	var buf bytes.Buffer
+	if err := NewJSONExporter().Export(context.Background(), &buf, rss, opts); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var doc struct {
+		Items   []map[string]string `json:"items"`
+		Summary []struct {
+			FeedURL string
+			Status  string
+			Items   int
+			Error   string
+		} `json:"summary"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("invalid JSON: %v\n%s", err, buf.String())
+	}
+	if doc.Items[1]["FeedTitle"] != "B" {
+		t.Errorf("items[1].FeedTitle = %q, want B", doc.Items[1]["FeedTitle"])
+	}
+	if len(doc.Summary) != 3 || doc.Summary[0].Items != 1 || doc.Summary[2].Status != "error" || doc.Summary[2].Error != "-boom" {
+		t.Errorf("summary = %+v", doc.Summary)
+	}
+}
+
+func TestNDJSONExporter_Export_BatchSummary(t *testing.T) {
+	rss, opts := batchFeed()
+
+	var buf bytes.Buffer
+	if err := NewNDJSONExporter().Export(context.Background(), &buf, rss, opts); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("got %d lines, want 2 items and a summary", len(lines))
+	}
+	var last struct {
+		Summary []map[string]any `json:"summary"`
+	}
+	if err := json.Unmarshal([]byte(lines[2]), &last); err != nil || len(last.Summary) != 3 {
+		t.Errorf("summary line = %s, error %v", lines[2], err)
+	}
+}
+
+func TestXLSXExporter_Export_BatchSummary(t *testing.T) {
+	rss, opts := batchFeed()
+	parts := readXLSX(t, rss, opts)
+
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Summary" sheetId="2" r:id="rId2"/>`) {
+		t.Errorf("workbook is missing the summary sheet: %s", parts["xl/workbook.xml"])
+	}
+	if !strings.Contains(parts["xl/_rels/workbook.xml.rels"], `Target="worksheets/sheet2.xml"`) {
+		t.Error("workbook relationships are missing the summary sheet")
+	}
+	summary := parts["xl/worksheets/sheet2.xml"]
+	for _, want := range []string{"Status", "https://c.example/feed", "-boom"} {
+		if !strings.Contains(summary, want) {
+			t.Errorf("summary sheet is missing %q", want)
+		}
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet1.xml"], "https://b.example/feed") {
+		t.Error("item sheet is missing the FeedURL column")
+	}
+}
@@ -0,0 +1,166 @@
+package services
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// xmlEncodingPattern finds the encoding named in an XML declaration
+var xmlEncodingPattern = regexp.MustCompile(`^\s*<\?xml[^>]*?\bencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+// fallbackEncoding decodes bodies that declare nothing usable and are not
+// valid UTF-8. windows-1252 maps every byte, so nothing is lost.
+var fallbackEncoding encoding.Encoding = charmap.Windows1252
+
+// toUTF8 transcodes a feed body to UTF-8. A byte order mark wins outright.
+// Otherwise the charset comes from the Content-Type header and the XML
+// declaration; when they disagree, or when the chosen charset does not fit
+// the bytes, the body is inspected to pick the one that decodes cleanly.
+func toUTF8(body []byte, contentType string) []byte {
+	if enc, size := bomEncoding(body); enc != nil {
+		if enc == unicode.UTF8 {
+			return body[size:]
+		}
+		return decodeOrKeep(enc, body[size:])
+	}
+
+	var candidates []encoding.Encoding
+	for _, label := range []string{httpCharset(contentType), xmlCharset(body)} {
+		enc := lookupCharset(label)
+		if enc == nil || containsEncoding(candidates, enc) {
+			continue
+		}
+		candidates = append(candidates, enc)
+	}
+
+	enc := detectCharset(body, candidates)
+	if enc == unicode.UTF8 {
+		return body
+	}
+	return decodeOrKeep(enc, body)
+}
+
+// detectCharset picks the encoding of a body from the declared candidates.
+// A single declaration is trusted unless it claims UTF-8 for bytes that are
+// not; conflicting declarations are resolved by trying each in turn.
+func detectCharset(body []byte, candidates []encoding.Encoding) encoding.Encoding {
+	validUTF8 := utf8.Valid(body)
+
+	switch len(candidates) {
+	case 0:
+		if validUTF8 {
+			return unicode.UTF8
+		}
+		return fallbackEncoding
+	case 1:
+		if candidates[0] == unicode.UTF8 && !validUTF8 {
+			return fallbackEncoding
+		}
+		return candidates[0]
+	}
+
+	if validUTF8 && containsEncoding(candidates, unicode.UTF8) {
+		return unicode.UTF8
+	}
+	for _, enc := range candidates {
+		if enc == unicode.UTF8 {
+			continue
+		}
+		if decoded, err := enc.NewDecoder().Bytes(body); err == nil && !bytes.ContainsRune(decoded, utf8.RuneError) {
+			return enc
+		}
+	}
+	if validUTF8 {
+		return unicode.UTF8
+	}
+	return fallbackEncoding
+}
+
+// bomEncoding returns the encoding announced by a byte order mark and the
+// length of the mark
+func bomEncoding(body []byte) (encoding.Encoding, int) {
+	switch {
+	case bytes.HasPrefix(body, []byte{0xEF, 0xBB, 0xBF}):
+		return unicode.UTF8, 3
+	case bytes.HasPrefix(body, []byte{0xFE, 0xFF}):
+		return unicode.UTF16(unicode.BigEndian, unicode.IgnoreBOM), 2
+	case bytes.HasPrefix(body, []byte{0xFF, 0xFE}):
+		return unicode.UTF16(unicode.LittleEndian, unicode.IgnoreBOM), 2
+	default:
+		return nil, 0
+	}
+}
+
+// httpCharset returns the charset parameter of a Content-Type header
+func httpCharset(contentType string) string {
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
+
+// xmlCharset returns the encoding named in the document's XML declaration
+func xmlCharset(body []byte) string {
+	if len(body) > 1024 {
+		body = body[:1024]
+	}
+	if match := xmlEncodingPattern.FindSubmatch(body); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// lookupCharset resolves a charset label using the WHATWG encoding names
+// browsers use, which for example treat ISO-8859-1 as windows-1252.
+// Unknown labels return nil.
+func lookupCharset(label string) encoding.Encoding {
+	label = strings.TrimSpace(label)
+	if label == "" {
+		return nil
+	}
+	enc, err := htmlindex.Get(label)
+	if err != nil {
+		return nil
+	}
+	if name, _ := htmlindex.Name(enc); name == "utf-8" {
+		return unicode.UTF8
+	}
+	return enc
+}
+
+// containsEncoding reports whether encodings includes enc
+func containsEncoding(encodings []encoding.Encoding, enc encoding.Encoding) bool {
+	for _, candidate := range encodings {
+		if candidate == enc {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeOrKeep transcodes body to UTF-8, returning it unchanged if the
+// decoder fails so the XML parser can report the problem
+func decodeOrKeep(enc encoding.Encoding, body []byte) []byte {
+	decoded, err := enc.NewDecoder().Bytes(body)
+	if err != nil {
+		return body
+	}
+	return decoded
+}
+
+// utf8CharsetReader lets encoding/xml accept documents whose declaration
+// names a legacy charset. Bodies are transcoded by toUTF8 before decoding,
+// so the input is passed through unchanged.
+func utf8CharsetReader(_ string, input io.Reader) (io.Reader, error) {
+	return input, nil
+}
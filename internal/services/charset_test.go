@@ -0,0 +1,134 @@
+package services
+
+import (
+	"testing"
+
+	"golang.org/x/text/encoding"
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/encoding/unicode"
+)
+
+// encodeString encodes UTF-8 text into a legacy charset for test fixtures
+func encodeString(t *testing.T, enc encoding.Encoding, s string) []byte {
+	t.Helper()
+	encoded, err := enc.NewEncoder().String(s)
+	if err != nil {
+		t.Fatalf("failed to encode %q: %v", s, err)
+	}
+	return []byte(encoded)
+}
+
+func TestToUTF8(t *testing.T) {
+	const latin = `<?xml version="1.0" encoding="ISO-8859-1"?><rss><title>Café “Zürich”</title></rss>`
+	const japaneseFeed = `<?xml version="1.0" encoding="Shift_JIS"?><rss><title>日本語のニュース</title></rss>`
+	const undeclared = `<rss><title>Café “Zürich”</title></rss>`
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+		want        string
+	}{
+		{
+			name: "XML declaration ISO-8859-1 read as windows-1252",
+			body: encodeString(t, charmap.Windows1252, latin),
+			want: latin,
+		},
+		{
+			name: "XML declaration Shift_JIS",
+			body: encodeString(t, japanese.ShiftJIS, japaneseFeed),
+			want: japaneseFeed,
+		},
+		{
+			name:        "HTTP charset without declaration",
+			body:        encodeString(t, japanese.ShiftJIS, `<rss><title>日本語</title></rss>`),
+			contentType: "application/rss+xml; charset=Shift_JIS",
+			want:        `<rss><title>日本語</title></rss>`,
+		},
+		{
+			name:        "HTTP charset and declaration agree",
+			body:        encodeString(t, charmap.Windows1252, latin),
+			contentType: "text/xml; charset=windows-1252",
+			want:        latin,
+		},
+		{
+			name:        "HTTP claims UTF-8 but body matches the declaration",
+			body:        encodeString(t, charmap.Windows1252, latin),
+			contentType: "text/xml; charset=utf-8",
+			want:        latin,
+		},
+		{
+			name:        "HTTP claims Latin-1 but body is UTF-8 as declared",
+			body:        []byte(`<?xml version="1.0" encoding="UTF-8"?><rss><title>Café</title></rss>`),
+			contentType: "text/xml; charset=iso-8859-1",
+			want:        `<?xml version="1.0" encoding="UTF-8"?><rss><title>Café</title></rss>`,
+		},
+		{
+			name:        "declaration claims Shift_JIS but body is Latin-1 per HTTP",
+			body:        encodeString(t, charmap.Windows1252, `<?xml version="1.0" encoding="Shift_JIS"?><rss><title>Ça coûte 5€</title></rss>`),
+			contentType: "text/xml; charset=windows-1252",
+			want:        `<?xml version="1.0" encoding="Shift_JIS"?><rss><title>Ça coûte 5€</title></rss>`,
+		},
+		{
+			name: "undeclared UTF-8 kept",
+			body: []byte(undeclared),
+			want: undeclared,
+		},
+		{
+			name: "undeclared legacy bytes fall back to windows-1252",
+			body: encodeString(t, charmap.Windows1252, undeclared),
+			want: undeclared,
+		},
+		{
+			name: "UTF-8 byte order mark stripped",
+			body: append([]byte{0xEF, 0xBB, 0xBF}, undeclared...),
+			want: undeclared,
+		},
+		{
+			name: "UTF-16 byte order mark",
+			body: encodeString(t, unicode.UTF16(unicode.LittleEndian, unicode.UseBOM), undeclared),
+			want: undeclared,
+		},
+		{
+			name:        "unknown labels ignored",
+			body:        []byte(undeclared),
+			contentType: "text/xml; charset=x-unknown",
+			want:        undeclared,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(toUTF8(tt.body, tt.contentType)); got != tt.want {
+				t.Errorf("toUTF8() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFeed_Charset(t *testing.T) {
+	body := encodeString(t, charmap.ISO8859_1, `<?xml version="1.0" encoding="ISO-8859-1"?>
+<rss version="2.0"><channel><title>Actualités</title><item><title>Été à Montréal</title></item></channel></rss>`)
+
+	rss, err := ParseFeed(body, "application/rss+xml")
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+	if rss.Channel.Title != "Actualités" {
+		t.Errorf("Channel.Title = %q, want %q", rss.Channel.Title, "Actualités")
+	}
+	if rss.Channel.Items[0].Title != "Été à Montréal" {
+		t.Errorf("Item.Title = %q, want %q", rss.Channel.Items[0].Title, "Été à Montréal")
+	}
+
+	atom := encodeString(t, japanese.ShiftJIS, `<?xml version="1.0" encoding="Shift_JIS"?>
+<feed xmlns="http://www.w3.org/2005/Atom"><title>ニュース</title><entry><title>速報</title></entry></feed>`)
+	rss, err = ParseFeed(atom, "application/atom+xml")
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+	if rss.Channel.Title != "ニュース" || rss.Channel.Items[0].Title != "速報" {
+		t.Errorf("titles = %q / %q, want ニュース / 速報", rss.Channel.Title, rss.Channel.Items[0].Title)
+	}
+}
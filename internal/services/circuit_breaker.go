@@ -0,0 +1,218 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+// BreakerState is the state of a host's circuit breaker
+type BreakerState string
+
+const (
+	// BreakerClosed lets requests through while counting consecutive failures
+	BreakerClosed BreakerState = "closed"
+	// BreakerOpen rejects requests until the open timeout has passed
+	BreakerOpen BreakerState = "open"
+	// BreakerHalfOpen lets a single probe request through to test the host
+	BreakerHalfOpen BreakerState = "half-open"
+)
+
+// BreakerStatus describes one host's breaker for reporting
+type BreakerStatus struct {
+	Host                string       `json:"host"`
+	State               BreakerState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutiveFailures"`
+	OpenedAt            *time.Time   `json:"openedAt,omitempty"`
+	RetryAt             *time.Time   `json:"retryAt,omitempty"`
+}
+
+// maxBreakerHosts bounds the hosts a circuit breaker tracks. Hosts come from
+// user-supplied URLs, so without a bound the map could grow without limit.
+const maxBreakerHosts = 10000
+
+// CircuitBreaker tracks upstream failures per host. After FailureThreshold
+// consecutive failures a host's breaker opens and requests fail fast with
+// errors.CircuitOpenError. Once the open timeout passes a single probe is let
+// through: success closes the breaker, failure opens it again.
+type CircuitBreaker struct {
+	mu               sync.Mutex
+	failureThreshold int
+	openTimeout      time.Duration
+	hosts            map[string]*hostBreaker
+	maxHosts         int
+	now              func() time.Time
+}
+
+// hostBreaker is the breaker state of a single host
+type hostBreaker struct {
+	state       BreakerState
+	failures    int
+	openedAt    time.Time
+	lastFailure time.Time
+	probing     bool
+}
+
+// NewCircuitBreaker creates a circuit breaker. A failure threshold of zero
+// or less returns nil, which disables circuit breaking.
+func NewCircuitBreaker(failureThreshold int, openTimeout time.Duration) *CircuitBreaker {
+	if failureThreshold <= 0 {
+		return nil
+	}
+	return &CircuitBreaker{
+		failureThreshold: failureThreshold,
+		openTimeout:      openTimeout,
+		hosts:            make(map[string]*hostBreaker),
+		maxHosts:         maxBreakerHosts,
+		now:              time.Now,
+	}
+}
+
+// Allow reports whether a request to host may proceed. Every allowed request
+// must be followed by a call to Success, Failure or Cancel.
+func (b *CircuitBreaker) Allow(host string) error {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	hb, ok := b.hosts[normalizeBreakerHost(host)]
+	if !ok {
+		return nil
+	}
+
+	switch hb.state {
+	case BreakerOpen:
+		retryAt := hb.openedAt.Add(b.openTimeout)
+		if b.now().Before(retryAt) {
+			return &errors.CircuitOpenError{Host: host, RetryAt: retryAt}
+		}
+		hb.state = BreakerHalfOpen
+		hb.probing = true
+		return nil
+	case BreakerHalfOpen:
+		if hb.probing {
+			return &errors.CircuitOpenError{Host: host, RetryAt: b.now().Add(b.openTimeout)}
+		}
+		hb.probing = true
+		return nil
+	default:
+		return nil
+	}
+}
+
+// Success records a request the host answered, closing its breaker
+func (b *CircuitBreaker) Success(host string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	// Healthy hosts are not tracked, which keeps the map small
+	delete(b.hosts, normalizeBreakerHost(host))
+}
+
+// Failure records a failed request, opening the breaker once the threshold
+// is reached or immediately if the failed request was a half-open probe
+func (b *CircuitBreaker) Failure(host string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	key := normalizeBreakerHost(host)
+	hb, ok := b.hosts[key]
+	if !ok {
+		b.prune()
+		hb = &hostBreaker{state: BreakerClosed}
+		b.hosts[key] = hb
+	}
+
+	hb.failures++
+	hb.lastFailure = b.now()
+	hb.probing = false
+	if hb.state == BreakerHalfOpen || hb.failures >= b.failureThreshold {
+		hb.state = BreakerOpen
+		hb.openedAt = b.now()
+	}
+}
+
+// prune makes room for a new host. Closed breakers whose last failure is
+// older than the open timeout are forgotten, and if the map is still full
+// the breaker with the oldest failure is dropped, sparing running probes.
+// The caller must hold b.mu.
+func (b *CircuitBreaker) prune() {
+	cutoff := b.now().Add(-b.openTimeout)
+	for host, hb := range b.hosts {
+		if hb.state == BreakerClosed && hb.lastFailure.Before(cutoff) {
+			delete(b.hosts, host)
+		}
+	}
+
+	for len(b.hosts) >= b.maxHosts {
+		oldest := ""
+		for host, hb := range b.hosts {
+			if !hb.probing && (oldest == "" || hb.lastFailure.Before(b.hosts[oldest].lastFailure)) {
+				oldest = host
+			}
+		}
+		if oldest == "" {
+			return
+		}
+		delete(b.hosts, oldest)
+	}
+}
+
+// Cancel ends a request that said nothing about the host's health, such as
+// one cancelled by the client, freeing a half-open breaker for another probe
+func (b *CircuitBreaker) Cancel(host string) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if hb, ok := b.hosts[normalizeBreakerHost(host)]; ok {
+		hb.probing = false
+	}
+}
+
+// Status returns the breaker of every host with recent failures, sorted by host
+func (b *CircuitBreaker) Status() []BreakerStatus {
+	if b == nil {
+		return nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	statuses := make([]BreakerStatus, 0, len(b.hosts))
+	for host, hb := range b.hosts {
+		status := BreakerStatus{
+			Host:                host,
+			State:               hb.state,
+			ConsecutiveFailures: hb.failures,
+		}
+		if hb.state != BreakerClosed {
+			openedAt := hb.openedAt
+			retryAt := hb.openedAt.Add(b.openTimeout)
+			status.OpenedAt = &openedAt
+			status.RetryAt = &retryAt
+		}
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Host < statuses[j].Host
+	})
+	return statuses
+}
+
+// normalizeBreakerHost lower-cases a host so breakers are shared regardless of case
+func normalizeBreakerHost(host string) string {
+	return strings.ToLower(host)
+}
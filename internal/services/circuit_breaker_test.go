@@ -0,0 +1,138 @@
+package services
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+func TestCircuitBreaker_Transitions(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(3, time.Minute)
+	breaker.now = func() time.Time { return now }
+
+	// Failures below the threshold keep the breaker closed
+	for range 2 {
+		if err := breaker.Allow("example.com"); err != nil {
+			t.Fatalf("Allow() error = %v, want closed breaker", err)
+		}
+		breaker.Failure("example.com")
+	}
+	if status := breaker.Status(); len(status) != 1 || status[0].State != BreakerClosed || status[0].ConsecutiveFailures != 2 {
+		t.Fatalf("Status() = %+v, want closed with 2 failures", status)
+	}
+
+	// The third consecutive failure opens it
+	breaker.Allow("example.com")
+	breaker.Failure("example.com")
+	err := breaker.Allow("EXAMPLE.com")
+	var circuitOpen *errors.CircuitOpenError
+	if !stderrors.As(err, &circuitOpen) {
+		t.Fatalf("Allow() error = %v, want CircuitOpenError", err)
+	}
+	if !circuitOpen.RetryAt.Equal(now.Add(time.Minute)) {
+		t.Errorf("RetryAt = %v, want %v", circuitOpen.RetryAt, now.Add(time.Minute))
+	}
+
+	// Other hosts are unaffected
+	if err := breaker.Allow("other.example.com"); err != nil {
+		t.Errorf("Allow(other host) error = %v", err)
+	}
+
+	// After the open timeout a single probe is allowed
+	now = now.Add(time.Minute)
+	if err := breaker.Allow("example.com"); err != nil {
+		t.Fatalf("Allow() error = %v, want half-open probe", err)
+	}
+	if status := breaker.Status(); status[0].State != BreakerHalfOpen {
+		t.Errorf("State = %s, want half-open", status[0].State)
+	}
+	if err := breaker.Allow("example.com"); err == nil {
+		t.Error("Allow() during probe should fail fast")
+	}
+
+	// A failed probe reopens the breaker
+	breaker.Failure("example.com")
+	if err := breaker.Allow("example.com"); err == nil {
+		t.Error("Allow() after failed probe should fail fast")
+	}
+
+	// A cancelled probe frees the slot, a successful one closes the breaker
+	now = now.Add(time.Minute)
+	breaker.Allow("example.com")
+	breaker.Cancel("example.com")
+	if err := breaker.Allow("example.com"); err != nil {
+		t.Fatalf("Allow() after cancelled probe error = %v", err)
+	}
+	breaker.Success("example.com")
+	if err := breaker.Allow("example.com"); err != nil {
+		t.Errorf("Allow() after successful probe error = %v", err)
+	}
+	if status := breaker.Status(); len(status) != 0 {
+		t.Errorf("Status() = %+v, want healthy hosts untracked", status)
+	}
+}
+
+func TestCircuitBreaker_ForgetsStaleHosts(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	breaker.maxHosts = 3
+
+	breaker.Failure("stale.example.com")
+	breaker.Failure("open.example.com")
+	breaker.Failure("open.example.com")
+
+	// A closed breaker is forgotten once its failure is older than the
+	// open timeout, an open one is kept
+	now = now.Add(2 * time.Minute)
+	breaker.Failure("new.example.com")
+	hosts := make(map[string]bool)
+	for _, status := range breaker.Status() {
+		hosts[status.Host] = true
+	}
+	if len(hosts) != 2 || !hosts["open.example.com"] || !hosts["new.example.com"] {
+		t.Errorf("tracked hosts = %v, want the open and the new host", hosts)
+	}
+
+	// A full map drops the host with the oldest failure
+	now = now.Add(time.Second)
+	breaker.Failure("a.example.com")
+	now = now.Add(time.Second)
+	breaker.Failure("b.example.com")
+	hosts = make(map[string]bool)
+	for _, status := range breaker.Status() {
+		hosts[status.Host] = true
+	}
+	if len(hosts) != 3 || hosts["open.example.com"] {
+		t.Errorf("tracked hosts = %v, want at most 3 without the oldest", hosts)
+	}
+}
+
+func TestCircuitBreaker_SuccessResetsFailures(t *testing.T) {
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.Failure("example.com")
+	breaker.Success("example.com")
+	breaker.Failure("example.com")
+	if err := breaker.Allow("example.com"); err != nil {
+		t.Errorf("Allow() error = %v, want failures reset by success", err)
+	}
+}
+
+func TestCircuitBreaker_Disabled(t *testing.T) {
+	breaker := NewCircuitBreaker(0, time.Minute)
+	if breaker != nil {
+		t.Fatal("NewCircuitBreaker(0) should return nil")
+	}
+	for range 10 {
+		breaker.Failure("example.com")
+	}
+	if err := breaker.Allow("example.com"); err != nil {
+		t.Errorf("Allow() on disabled breaker error = %v", err)
+	}
+	if status := breaker.Status(); status != nil {
+		t.Errorf("Status() = %+v, want nil", status)
+	}
+}
@@ -0,0 +1,265 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+)
+
+// DefaultMultiValueSeparator joins multi-valued fields such as categories
+const DefaultMultiValueSeparator = "|"
+
+// ColumnKind describes the type of value a column holds, so typed formats
+// such as XLSX can render dates and links natively
+type ColumnKind int
+
+const (
+	KindText ColumnKind = iota
+	KindDate
+	KindURL
+)
+
+// Column describes a single exportable field
+type Column struct {
+	// Field is the identifier used to select the column
+	Field string
+	// Header is the column label written to the header row
+	Header string
+	// HTML marks fields whose value is HTML and subject to sanitization
+	HTML bool
+	// Kind is the type of value the column holds
+	Kind ColumnKind
+
+	value func(item *models.Item, channel *models.Channel, sep string) string
+	// date returns the parsed value of date columns, which is reformatted
+	// when a date format other than DateRaw is selected
+	date func(item *models.Item, channel *models.Channel) time.Time
+}
+
+// Value extracts the column value for an item
+func (c Column) Value(item *models.Item, channel *models.Channel, sep string) string {
+	return c.value(item, channel, sep)
+}
+
+// feedColumns are prepended to every row when feed columns are requested
+var feedColumns = []Column{
+	{Field: "feedTitle", Header: "FeedTitle", value: func(item *models.Item, ch *models.Channel, _ string) string {
+		// Merged batch items carry the title of the feed they came from
+		if item != nil && item.FeedTitle != "" {
+			return item.FeedTitle
+		}
+		return ch.Title
+	}},
+	{Field: "feedLink", Header: "FeedLink", Kind: KindURL, value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.GetLink()
+	}},
+	{Field: "feedLanguage", Header: "FeedLanguage", value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.Language
+	}},
+}
+
+// feedURLColumn names the feed an item was fetched from
+var feedURLColumn = Column{Field: "feedURL", Header: "FeedURL", Kind: KindURL, value: func(item *models.Item, _ *models.Channel, _ string) string {
+	return item.FeedURL
+}}
+
+// batchColumns are prepended to every row of a batch export so items of
+// different feeds can be told apart
+var batchColumns = []Column{feedURLColumn, feedColumns[0]}
+
+// itemColumns lists every item field in default export order
+var itemColumns = []Column{
+	{Field: "title", Header: "Title", value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return item.Title
+	}},
+	{Field: "link", Header: "Link", Kind: KindURL, value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return item.Link
+	}},
+	{Field: "description", Header: "Description", HTML: true, value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return item.Description
+	}},
+	{Field: "pubDate", Header: "PubDate", Kind: KindDate, value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return item.PubDate
+	}, date: func(item *models.Item, _ *models.Channel) time.Time {
+		return itemPublished(item)
+	}},
+	{Field: "imageURL", Header: "ImageURL", Kind: KindURL, value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return item.GetImageURL()
+	}},
+	{Field: "content", Header: "Content", HTML: true, value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return item.ContentEncoded
+	}},
+	{Field: "guid", Header: "GUID", value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return strings.TrimSpace(item.GUID.Value)
+	}},
+	{Field: "guidIsPermaLink", Header: "GUIDIsPermaLink", value: func(item *models.Item, _ *models.Channel, _ string) string {
+		if strings.TrimSpace(item.GUID.Value) == "" {
+			return ""
+		}
+		return strconv.FormatBool(item.GUID.IsPermaLinkValue())
+	}},
+	{Field: "author", Header: "Author", value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return string(item.Author)
+	}},
+	{Field: "dcCreator", Header: "DCCreator", value: func(item *models.Item, _ *models.Channel, sep string) string {
+		return strings.Join(item.Creators, sep)
+	}},
+	{Field: "categories", Header: "Categories", value: func(item *models.Item, _ *models.Channel, sep string) string {
+		values := make([]string, len(item.Categories))
+		for i, category := range item.Categories {
+			values[i] = strings.TrimSpace(category.Value)
+		}
+		return strings.Join(values, sep)
+	}},
+	{Field: "categoryDomains", Header: "CategoryDomains", value: func(item *models.Item, _ *models.Channel, sep string) string {
+		// Domains stay aligned with Categories, so empty entries are kept
+		// unless no category has a domain at all
+		values := make([]string, len(item.Categories))
+		hasDomain := false
+		for i, category := range item.Categories {
+			values[i] = strings.TrimSpace(category.Domain)
+			hasDomain = hasDomain || values[i] != ""
+		}
+		if !hasDomain {
+			return ""
+		}
+		return strings.Join(values, sep)
+	}},
+	{Field: "comments", Header: "Comments", Kind: KindURL, value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return string(item.Comments)
+	}},
+	{Field: "enclosureURL", Header: "EnclosureURL", value: func(item *models.Item, _ *models.Channel, sep string) string {
+		return joinEnclosures(item.Enclosures, sep, func(e models.Enclosure) string { return e.URL })
+	}},
+	{Field: "enclosureLength", Header: "EnclosureLength", value: func(item *models.Item, _ *models.Channel, sep string) string {
+		return joinEnclosures(item.Enclosures, sep, func(e models.Enclosure) string { return e.Length })
+	}},
+	{Field: "enclosureType", Header: "EnclosureType", value: func(item *models.Item, _ *models.Channel, sep string) string {
+		return joinEnclosures(item.Enclosures, sep, func(e models.Enclosure) string { return e.Type })
+	}},
+	{Field: "source", Header: "Source", value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return strings.TrimSpace(item.Source.Value)
+	}},
+	{Field: "sourceURL", Header: "SourceURL", Kind: KindURL, value: func(item *models.Item, _ *models.Channel, _ string) string {
+		return item.Source.URL
+	}},
+}
+
+// pubDateRawColumn keeps the publication date exactly as the feed wrote it.
+// It is added after PubDate by default when dates are reformatted.
+var pubDateRawColumn = Column{Field: "pubDateRaw", Header: "PubDateRaw", value: func(item *models.Item, _ *models.Channel, _ string) string {
+	return item.PubDate
+}}
+
+// channelColumns make up the single row of a channel summary export
+var channelColumns = []Column{
+	{Field: "title", Header: "Title", value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.Title
+	}},
+	{Field: "link", Header: "Link", Kind: KindURL, value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.GetLink()
+	}},
+	{Field: "description", Header: "Description", HTML: true, value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.Description
+	}},
+	{Field: "language", Header: "Language", value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.Language
+	}},
+	{Field: "lastBuildDate", Header: "LastBuildDate", Kind: KindDate, value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.LastBuildDate
+	}, date: func(_ *models.Item, ch *models.Channel) time.Time {
+		return channelLastBuild(ch)
+	}},
+	{Field: "imageURL", Header: "ImageURL", Kind: KindURL, value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.Image.URL
+	}},
+	{Field: "generator", Header: "Generator", value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return ch.Generator
+	}},
+	{Field: "itemCount", Header: "ItemCount", value: func(_ *models.Item, ch *models.Channel, _ string) string {
+		return strconv.Itoa(len(ch.Items))
+	}},
+}
+
+// defaultItemColumns returns the item columns exported when none are
+// selected, with the raw publication date after PubDate if requested
+func defaultItemColumns(withRawDate bool) []Column {
+	if !withRawDate {
+		return itemColumns
+	}
+	columns := make([]Column, 0, len(itemColumns)+1)
+	for _, column := range itemColumns {
+		columns = append(columns, column)
+		if column.Field == "pubDate" {
+			columns = append(columns, pubDateRawColumn)
+		}
+	}
+	return columns
+}
+
+// LookupColumn finds a column by field name, ignoring case
+func LookupColumn(field string) (Column, bool) {
+	for _, columns := range [][]Column{itemColumns, {pubDateRawColumn}, feedColumns, {feedURLColumn}} {
+		for _, column := range columns {
+			if strings.EqualFold(column.Field, field) {
+				return column, true
+			}
+		}
+	}
+	return Column{}, false
+}
+
+// ParseColumns parses a column selection such as "link:URL,title:Headline,pubDate".
+// Each entry names a field, optionally followed by a colon and a header label.
+func ParseColumns(spec string) ([]Column, error) {
+	var columns []Column
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		field, label, hasLabel := strings.Cut(entry, ":")
+		field = strings.TrimSpace(field)
+		column, ok := LookupColumn(field)
+		if !ok {
+			return nil, &errors.ValidationError{
+				Field:   "columns",
+				Message: fmt.Sprintf("unknown field %q", field),
+			}
+		}
+
+		if hasLabel {
+			label = strings.TrimSpace(label)
+			if label == "" {
+				return nil, &errors.ValidationError{
+					Field:   "columns",
+					Message: fmt.Sprintf("empty header label for field %q", field),
+				}
+			}
+			column.Header = label
+		}
+		columns = append(columns, column)
+	}
+
+	if len(columns) == 0 {
+		return nil, &errors.ValidationError{
+			Field:   "columns",
+			Message: "at least one column must be selected",
+		}
+	}
+	return columns, nil
+}
+
+// joinEnclosures joins one attribute of every enclosure with the separator
+func joinEnclosures(enclosures []models.Enclosure, sep string, attr func(models.Enclosure) string) string {
+	values := make([]string, len(enclosures))
+	for i, enclosure := range enclosures {
+		values[i] = strings.TrimSpace(attr(enclosure))
+	}
+	return strings.Join(values, sep)
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+func TestParseColumns(t *testing.T) {
+	tests := []struct {
+		name        string
+		spec        string
+		wantFields  []string
+		wantHeaders []string
+		wantErr     bool
+	}{
+		{
+			name:        "fields with labels",
+			spec:        "link:URL,title:Headline,pubDate",
+			wantFields:  []string{"link", "title", "pubDate"},
+			wantHeaders: []string{"URL", "Headline", "PubDate"},
+		},
+		{
+			name:        "case insensitive with whitespace",
+			spec:        " TITLE , feedtitle : Feed ",
+			wantFields:  []string{"title", "feedTitle"},
+			wantHeaders: []string{"Title", "Feed"},
+		},
+		{
+			name:    "unknown field",
+			spec:    "title,bogus",
+			wantErr: true,
+		},
+		{
+			name:    "empty label",
+			spec:    "title:",
+			wantErr: true,
+		},
+		{
+			name:    "no columns",
+			spec:    " , ",
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			columns, err := ParseColumns(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseColumns() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var validationErr *errors.ValidationError
+				if !stderrors.As(err, &validationErr) || validationErr.Field != "columns" {
+					t.Errorf("ParseColumns() error = %v, want ValidationError on columns", err)
+				}
+				return
+			}
+			if len(columns) != len(tt.wantFields) {
+				t.Fatalf("len(columns) = %d, want %d", len(columns), len(tt.wantFields))
+			}
+			for i, column := range columns {
+				if column.Field != tt.wantFields[i] {
+					t.Errorf("columns[%d].Field = %q, want %q", i, column.Field, tt.wantFields[i])
+				}
+				if column.Header != tt.wantHeaders[i] {
+					t.Errorf("columns[%d].Header = %q, want %q", i, column.Header, tt.wantHeaders[i])
+				}
+			}
+		})
+	}
+}
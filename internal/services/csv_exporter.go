@@ -1,14 +1,76 @@
 package services
 
 import (
+	"bufio"
 	"context"
 	"encoding/csv"
+	"fmt"
 	"io"
+	"strings"
 
+	"rss-feed-to-csv/internal/errors"
 	"rss-feed-to-csv/internal/models"
 	"rss-feed-to-csv/internal/utils"
 )
 
+// utf8BOM is written ahead of the header row when CSVDialect.BOM is set
+const utf8BOM = "\uFEFF"
+
+// csvDelimiters maps delimiter names accepted by ParseCSVDelimiter to runes
+var csvDelimiters = map[string]rune{
+	"comma":     ',',
+	"semicolon": ';',
+	"tab":       '\t',
+	"pipe":      '|',
+}
+
+// CSVDialect controls the low-level layout of CSV output
+type CSVDialect struct {
+	// Delimiter separates fields; zero means a comma
+	Delimiter rune
+	// BOM writes a UTF-8 byte order mark before the header row
+	BOM bool
+	// CRLF ends lines with \r\n instead of \n
+	CRLF bool
+	// AlwaysQuote quotes every field, not only those that need it
+	AlwaysQuote bool
+}
+
+// ParseCSVDelimiter converts a delimiter name (comma, semicolon, tab or pipe)
+// to the rune it stands for
+func ParseCSVDelimiter(name string) (rune, error) {
+	delimiter, ok := csvDelimiters[strings.ToLower(name)]
+	if !ok {
+		return 0, &errors.ValidationError{
+			Field:   "delimiter",
+			Message: fmt.Sprintf("unsupported delimiter %q: must be comma, semicolon, tab or pipe", name),
+		}
+	}
+	return delimiter, nil
+}
+
+// csvRecordWriter is the subset of csv.Writer used by CSVExporter
+type csvRecordWriter interface {
+	Write(record []string) error
+	Flush()
+	Error() error
+}
+
+// newRecordWriter returns a record writer for the dialect
+func (d CSVDialect) newRecordWriter(w io.Writer) csvRecordWriter {
+	delimiter := d.Delimiter
+	if delimiter == 0 {
+		delimiter = ','
+	}
+	if d.AlwaysQuote {
+		return &quotingCSVWriter{w: bufio.NewWriter(w), comma: delimiter, useCRLF: d.CRLF}
+	}
+	writer := csv.NewWriter(w)
+	writer.Comma = delimiter
+	writer.UseCRLF = d.CRLF
+	return writer
+}
+
 // CSVExporter handles exporting RSS data to CSV format
 type CSVExporter struct {
 	sanitizer *utils.HTMLSanitizer
@@ -21,14 +83,34 @@ func NewCSVExporter() *CSVExporter {
 	}
 }
 
+// ContentType returns the media type of CSV output
+func (e *CSVExporter) ContentType() string {
+	return "text/csv"
+}
 
-// Export writes RSS items to CSV format
-func (e *CSVExporter) Export(ctx context.Context, w io.Writer, rss *models.RSS, sanitizeHTML bool) error {
-	writer := csv.NewWriter(w)
+// FileExtension returns the file extension for CSV downloads
+func (e *CSVExporter) FileExtension() string {
+	return "csv"
+}
+
+// Export writes RSS items, or a channel summary, to CSV format
+func (e *CSVExporter) Export(ctx context.Context, w io.Writer, rss *models.RSS, opts ExportOptions) error {
+	if opts.CSV.BOM {
+		if _, err := io.WriteString(w, utf8BOM); err != nil {
+			return err
+		}
+	}
+
+	writer := opts.CSV.newRecordWriter(w)
 	defer writer.Flush()
 
+	columns := opts.columns()
+
 	// Write headers
-	headers := []string{"Title", "Link", "Description", "PubDate", "ImageURL", "Content"}
+	headers := make([]string, len(columns))
+	for i, column := range columns {
+		headers[i] = column.Header
+	}
 	if err := writer.Write(headers); err != nil {
 		return err
 	}
@@ -38,29 +120,110 @@ func (e *CSVExporter) Export(ctx context.Context, w io.Writer, rss *models.RSS,
 	}
 
 	// Write data rows
-	for _, item := range rss.Channel.Items {
-		description := item.Description
-		content := item.ContentEncoded
-		
-		// Apply HTML sanitization if requested
-		if sanitizeHTML {
-			description = e.sanitizer.StripHTML(description)
-			content = e.sanitizer.StripHTML(content)
-		}
-		
-		record := []string{
-			item.Title,
-			item.Link,
-			description,
-			item.PubDate,
-			item.GetImageURL(),
-			content,
+rows:
+	for _, item := range opts.rows(rss) {
+		record := make([]string, len(columns))
+		for i, column := range columns {
+			value, ok := opts.formulaProtection().neutralize(opts.cellValue(e.sanitizer, column, item, &rss.Channel))
+			if !ok {
+				continue rows
+			}
+			record[i] = value
 		}
-		
+
 		if err := writer.Write(record); err != nil {
 			return err
 		}
 	}
 
+	if len(opts.Summary) > 0 {
+		return e.writeSummary(writer, opts)
+	}
 	return nil
-}
\ No newline at end of file
+}
+
+// writeSummary appends the per-feed report of a batch export after a blank
+// line. Its cells are protected against formulas like the items, but rows
+// are never dropped.
+func (e *CSVExporter) writeSummary(writer csvRecordWriter, opts ExportOptions) error {
+	records := [][]string{{}, append([]string(nil), summaryHeaders...)}
+	for _, report := range opts.Summary {
+		records = append(records, report.record())
+	}
+
+	for _, record := range records {
+		for i, value := range record {
+			if opts.formulaProtection() != FormulaOff {
+				record[i], _ = FormulaEscape.neutralize(value)
+			}
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// quotingCSVWriter writes CSV records with every field quoted. It follows
+// csv.Writer's rules for escaping quotes and translating line breaks.
+type quotingCSVWriter struct {
+	w       *bufio.Writer
+	comma   rune
+	useCRLF bool
+}
+
+// Write writes a single quoted record
+func (q *quotingCSVWriter) Write(record []string) error {
+	for i, field := range record {
+		if i > 0 {
+			q.w.WriteRune(q.comma)
+		}
+		q.w.WriteByte('"')
+		// Text between special characters is copied as bytes, so invalid
+		// UTF-8 comes out unchanged as it does from csv.Writer
+		for len(field) > 0 {
+			i := strings.IndexAny(field, "\"\r\n")
+			if i < 0 {
+				i = len(field)
+			}
+			q.w.WriteString(field[:i])
+			field = field[i:]
+			if len(field) == 0 {
+				break
+			}
+			switch field[0] {
+			case '"':
+				q.w.WriteString(`""`)
+			case '\r':
+				// With CRLF line endings \r is dropped and \n written as \r\n
+				if !q.useCRLF {
+					q.w.WriteByte('\r')
+				}
+			case '\n':
+				if q.useCRLF {
+					q.w.WriteString("\r\n")
+				} else {
+					q.w.WriteByte('\n')
+				}
+			}
+			field = field[1:]
+		}
+		q.w.WriteByte('"')
+	}
+	if q.useCRLF {
+		_, err := q.w.WriteString("\r\n")
+		return err
+	}
+	return q.w.WriteByte('\n')
+}
+
+// Flush writes any buffered data to the underlying writer
+func (q *quotingCSVWriter) Flush() {
+	q.w.Flush()
+}
+
+// Error reports any error from a previous Write or Flush
+func (q *quotingCSVWriter) Error() error {
+	_, err := q.w.Write(nil)
+	return err
+}
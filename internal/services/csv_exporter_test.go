@@ -154,7 +154,7 @@ func TestCSVExporter_Export(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			var buf bytes.Buffer
-			err := exporter.Export(ctx, &buf, tt.rss, tt.sanitizeHTML)
+			err := exporter.Export(ctx, &buf, tt.rss, ExportOptions{SanitizeHTML: tt.sanitizeHTML})
 			if err != nil {
 				t.Fatalf("Export() error = %v", err)
 			}
@@ -201,12 +201,439 @@ func TestCSVExporter_Export_WriterError(t *testing.T) {
 		},
 	}
 
-	err := exporter.Export(ctx, failWriter, rss, false)
+	err := exporter.Export(ctx, failWriter, rss, ExportOptions{})
 	if err == nil {
 		t.Error("Export() should return error when writer fails")
 	}
 }
 
+func TestCSVExporter_Export_FeedColumns(t *testing.T) {
+	exporter := NewCSVExporter()
+
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Title:    "Example Feed",
+			Language: "en-us",
+			Items: []models.Item{
+				{Title: "Item 1", Link: "https://example.com/1"},
+			},
+		},
+	}
+	rss.Channel.SetLink("https://example.com/")
+
+	var buf bytes.Buffer
+	if err := exporter.Export(context.Background(), &buf, rss, ExportOptions{IncludeFeedColumns: true}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+
+	wantHeaders := []string{"FeedTitle", "FeedLink", "FeedLanguage", "Title", "Link", "Description", "PubDate", "ImageURL", "Content"}
+	for i, want := range wantHeaders {
+		if records[0][i] != want {
+			t.Errorf("Header[%d] = %q, want %q", i, records[0][i], want)
+		}
+	}
+	wantRow := []string{"Example Feed", "https://example.com/", "en-us", "Item 1", "https://example.com/1"}
+	for i, want := range wantRow {
+		if records[1][i] != want {
+			t.Errorf("Row[%d] = %q, want %q", i, records[1][i], want)
+		}
+	}
+}
+
+func TestCSVExporter_Export_ItemFields(t *testing.T) {
+	exporter := NewCSVExporter()
+
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Items: []models.Item{
+				{
+					Title:      "Item 1",
+					GUID:       models.GUID{Value: "https://example.com/1"},
+					Author:     "editor@example.com",
+					Creators:   []string{"Jane Doe", "John Roe"},
+					Categories: []models.Category{{Value: "News", Domain: "https://example.com/s"}, {Value: "Tech"}},
+					Comments:   "https://example.com/1#comments",
+					Enclosures: []models.Enclosure{
+						{URL: "https://example.com/a.mp3", Length: "100", Type: "audio/mpeg"},
+						{URL: "https://example.com/b.mp3", Length: "200", Type: "audio/mpeg"},
+					},
+					Source: models.Source{URL: "https://other.example.com/rss", Value: "Other"},
+				},
+				{Title: "Item 2"},
+			},
+		},
+	}
+
+	tests := []struct {
+		name      string
+		separator string
+		want      map[string]string
+	}{
+		{
+			name: "default separator",
+			want: map[string]string{
+				"GUID":            "https://example.com/1",
+				"GUIDIsPermaLink": "true",
+				"Author":          "editor@example.com",
+				"DCCreator":       "Jane Doe|John Roe",
+				"Categories":      "News|Tech",
+				"CategoryDomains": "https://example.com/s|",
+				"Comments":        "https://example.com/1#comments",
+				"EnclosureURL":    "https://example.com/a.mp3|https://example.com/b.mp3",
+				"EnclosureLength": "100|200",
+				"EnclosureType":   "audio/mpeg|audio/mpeg",
+				"Source":          "Other",
+				"SourceURL":       "https://other.example.com/rss",
+			},
+		},
+		{
+			name:      "custom separator",
+			separator: "; ",
+			want: map[string]string{
+				"DCCreator":  "Jane Doe; John Roe",
+				"Categories": "News; Tech",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			opts := ExportOptions{MultiValueSeparator: tt.separator}
+			if err := exporter.Export(context.Background(), &buf, rss, opts); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to read CSV: %v", err)
+			}
+
+			row := make(map[string]string)
+			empty := make(map[string]string)
+			for i, header := range records[0] {
+				row[header] = records[1][i]
+				empty[header] = records[2][i]
+			}
+			for header, want := range tt.want {
+				if row[header] != want {
+					t.Errorf("%s = %q, want %q", header, row[header], want)
+				}
+				if header != "Title" && empty[header] != "" {
+					t.Errorf("%s for empty item = %q, want empty", header, empty[header])
+				}
+			}
+		})
+	}
+}
+
+func TestCSVExporter_Export_SelectedColumns(t *testing.T) {
+	exporter := NewCSVExporter()
+
+	columns, err := ParseColumns("link:URL,title:Headline,pubDate")
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Items: []models.Item{
+				{Title: "Item 1", Link: "https://example.com/1", PubDate: "Mon, 02 Jan 2006", Description: "ignored"},
+			},
+		},
+	}
+
+	var buf bytes.Buffer
+	if err := exporter.Export(context.Background(), &buf, rss, ExportOptions{Columns: columns}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+
+	wantHeaders := []string{"URL", "Headline", "PubDate"}
+	wantRow := []string{"https://example.com/1", "Item 1", "Mon, 02 Jan 2006"}
+	if strings.Join(records[0], ",") != strings.Join(wantHeaders, ",") {
+		t.Errorf("Headers = %v, want %v", records[0], wantHeaders)
+	}
+	if strings.Join(records[1], ",") != strings.Join(wantRow, ",") {
+		t.Errorf("Row = %v, want %v", records[1], wantRow)
+	}
+}
+
+func TestCSVExporter_Export_ChannelSummary(t *testing.T) {
+	exporter := NewCSVExporter()
+
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Title:         "Example Feed",
+			Description:   "All the news",
+			Language:      "en",
+			LastBuildDate: "Mon, 02 Jan 2006 15:04:05 GMT",
+			Image:         models.ChannelImage{URL: "https://example.com/logo.png"},
+			Generator:     "Hugo",
+			Items:         []models.Item{{Title: "Item 1"}, {Title: "Item 2"}},
+		},
+	}
+	rss.Channel.SetLink("https://example.com/")
+
+	var buf bytes.Buffer
+	if err := exporter.Export(context.Background(), &buf, rss, ExportOptions{Mode: ModeChannel}); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if len(records) != 2 {
+		t.Fatalf("Number of rows = %d, want 2", len(records))
+	}
+
+	want := []string{"Example Feed", "https://example.com/", "All the news", "en", "Mon, 02 Jan 2006 15:04:05 GMT", "https://example.com/logo.png", "Hugo", "2"}
+	for i, w := range want {
+		if records[1][i] != w {
+			t.Errorf("%s = %q, want %q", records[0][i], records[1][i], w)
+		}
+	}
+}
+
+func TestCSVExporter_Export_Dialect(t *testing.T) {
+	exporter := NewCSVExporter()
+	columns, err := ParseColumns("title,link")
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Items: []models.Item{
+				{Title: "Say \"hi\"; bye", Link: "https://example.com/1"},
+				{Title: "Two\nlines", Link: ""},
+			},
+		},
+	}
+
+	tests := []struct {
+		name    string
+		dialect CSVDialect
+		want    string
+	}{
+		{
+			name:    "default",
+			dialect: CSVDialect{},
+			want:    "Title,Link\n\"Say \"\"hi\"\"; bye\",https://example.com/1\n\"Two\nlines\",\n",
+		},
+		{
+			name:    "semicolon with BOM and CRLF",
+			dialect: CSVDialect{Delimiter: ';', BOM: true, CRLF: true},
+			want:    "\uFEFFTitle;Link\r\n\"Say \"\"hi\"\"; bye\";https://example.com/1\r\n\"Two\r\nlines\";\r\n",
+		},
+		{
+			name:    "tab",
+			dialect: CSVDialect{Delimiter: '\t'},
+			want:    "Title\tLink\n\"Say \"\"hi\"\"; bye\"\thttps://example.com/1\n\"Two\nlines\"\t\n",
+		},
+		{
+			name:    "always quote with pipe",
+			dialect: CSVDialect{Delimiter: '|', AlwaysQuote: true},
+			want:    "\"Title\"|\"Link\"\n\"Say \"\"hi\"\"; bye\"|\"https://example.com/1\"\n\"Two\nlines\"|\"\"\n",
+		},
+		{
+			name:    "always quote with CRLF",
+			dialect: CSVDialect{AlwaysQuote: true, CRLF: true},
+			want:    "\"Title\",\"Link\"\r\n\"Say \"\"hi\"\"; bye\",\"https://example.com/1\"\r\n\"Two\r\nlines\",\"\"\r\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := exporter.Export(context.Background(), &buf, rss, ExportOptions{Columns: columns, CSV: tt.dialect}); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("Export() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCSVExporter_Export_AlwaysQuoteBytes(t *testing.T) {
+	columns, err := ParseColumns("title")
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+	rss := &models.RSS{Channel: models.Channel{Items: []models.Item{{Title: "a\xffb\rc"}}}}
+
+	// Invalid UTF-8 and lone carriage returns are written as they are in
+	// both quoting modes
+	for dialect, want := range map[string]string{
+		"minimal": "Title\n\"a\xffb\rc\"\n",
+		"all":     "\"Title\"\n\"a\xffb\rc\"\n",
+	} {
+		var buf bytes.Buffer
+		opts := ExportOptions{Columns: columns, CSV: CSVDialect{AlwaysQuote: dialect == "all"}}
+		if err := NewCSVExporter().Export(context.Background(), &buf, rss, opts); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+		if got := buf.String(); got != want {
+			t.Errorf("quote=%s Export() = %q, want %q", dialect, got, want)
+		}
+	}
+}
+
+func TestCSVExporter_Export_AlwaysQuoteWriterError(t *testing.T) {
+	rss := &models.RSS{Channel: models.Channel{Items: []models.Item{{Title: "Item 1"}}}}
+	opts := ExportOptions{CSV: CSVDialect{AlwaysQuote: true}}
+	if err := NewCSVExporter().Export(context.Background(), &failingWriter{}, rss, opts); err == nil {
+		t.Error("Export() should return error when writer fails")
+	}
+}
+
+func TestParseCSVDelimiter(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    rune
+		wantErr bool
+	}{
+		{"comma", ',', false},
+		{"semicolon", ';', false},
+		{"TAB", '\t', false},
+		{"pipe", '|', false},
+		{";", 0, true},
+		{"colon", 0, true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseCSVDelimiter(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseCSVDelimiter(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseCSVDelimiter(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+// formulaItem returns an item and channel whose every exported field starts
+// with a formula trigger
+func formulaItem() *models.RSS {
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Title:         "=feed()",
+			Description:   "+desc",
+			Language:      "-en",
+			LastBuildDate: "@date",
+			Image:         models.ChannelImage{URL: "=image()"},
+			Generator:     "=gen()",
+			Items: []models.Item{{
+				Title:          "=HYPERLINK(\"https://evil.example\",\"click\")",
+				Link:           "+link",
+				Description:    "-2+3",
+				PubDate:        "@pubDate",
+				GUID:           models.GUID{Value: "=guid()"},
+				Author:         "\t=author()",
+				Creators:       []string{"=creator()"},
+				Categories:     []models.Category{{Domain: "@domain", Value: "=category()"}},
+				Comments:       "\r=comments()",
+				Enclosures:     []models.Enclosure{{URL: "=url()", Length: "-1", Type: "+type"}},
+				Source:         models.Source{URL: "@source", Value: "=source()"},
+				ContentEncoded: "=content()",
+				MediaContent:   []models.MediaContent{{URL: "=media()", Medium: "image"}},
+			}},
+		},
+	}
+	rss.Channel.SetLink("-channelLink")
+	return rss
+}
+
+func TestCSVExporter_Export_FormulaProtection(t *testing.T) {
+	exporter := NewCSVExporter()
+
+	// Every item and feed column, then every channel column
+	modes := []ExportOptions{
+		{IncludeFeedColumns: true},
+		{Mode: ModeChannel},
+	}
+
+	for _, base := range modes {
+		for _, protection := range []FormulaProtection{"", FormulaEscape, FormulaStrip} {
+			opts := base
+			opts.FormulaProtection = protection
+
+			var buf bytes.Buffer
+			if err := exporter.Export(context.Background(), &buf, formulaItem(), opts); err != nil {
+				t.Fatalf("Export() error = %v", err)
+			}
+			records, err := csv.NewReader(&buf).ReadAll()
+			if err != nil {
+				t.Fatalf("Failed to read CSV: %v", err)
+			}
+			if len(records) != 2 {
+				t.Fatalf("Number of rows = %d, want 2", len(records))
+			}
+
+			for i, header := range records[0] {
+				cell := records[1][i]
+				if isFormula(cell) {
+					t.Errorf("mode %q: %s = %q is still a formula", protection, header, cell)
+				}
+				if protection != FormulaStrip && header != "GUIDIsPermaLink" && header != "ItemCount" && !strings.HasPrefix(cell, "'") {
+					t.Errorf("mode %q: %s = %q, want apostrophe prefix", protection, header, cell)
+				}
+			}
+		}
+	}
+}
+
+func TestCSVExporter_Export_FormulaReject(t *testing.T) {
+	exporter := NewCSVExporter()
+
+	// Each column is exported on its own so every column must reject the row.
+	// Feed columns repeat on every row, so they reject the safe item too.
+	for _, column := range append(append([]Column{}, feedColumns...), itemColumns...) {
+		if column.Field == "guidIsPermaLink" {
+			continue
+		}
+		rss := formulaItem()
+		rss.Channel.Items = append(rss.Channel.Items, models.Item{Title: "Safe"})
+
+		var buf bytes.Buffer
+		opts := ExportOptions{Columns: []Column{column}, FormulaProtection: FormulaReject}
+		if err := exporter.Export(context.Background(), &buf, rss, opts); err != nil {
+			t.Fatalf("Export() error = %v", err)
+		}
+
+		wantLines := 2
+		if strings.HasPrefix(column.Field, "feed") {
+			wantLines = 1
+		}
+		if got := strings.Count(buf.String(), "\n"); got != wantLines {
+			t.Errorf("%s: Export() = %q, want %d lines", column.Field, buf.String(), wantLines)
+		}
+	}
+}
+
+func TestCSVExporter_Export_FormulaOff(t *testing.T) {
+	var buf bytes.Buffer
+	columns, _ := ParseColumns("title")
+	opts := ExportOptions{Columns: columns, FormulaProtection: FormulaOff}
+	if err := NewCSVExporter().Export(context.Background(), &buf, formulaItem(), opts); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "\n\"=HYPERLINK(") {
+		t.Errorf("Export() = %q, want formula written verbatim", buf.String())
+	}
+}
+
 // Define a test error
 var testWriteError = errors.New("test write error")
 
@@ -222,4 +649,47 @@ func (w *failingWriter) Write(p []byte) (n int, err error) {
 	}
 	w.writes++
 	return len(p), nil
-}
\ No newline at end of file
+}
+
+func TestCSVExporter_Export_DateFormat(t *testing.T) {
+	exporter := NewCSVExporter()
+
+	rss, err := ParseFeed([]byte(`<rss version="2.0"><channel>
+		<item><title>One</title><pubDate>Tue, 02 Jan 2024 15:04:05 EST</pubDate></item>
+		<item><title>Two</title><pubDate>2024-01-03T08:00:00Z</pubDate></item>
+		<item><title>Three</title><pubDate>sometime last week</pubDate></item>
+	</channel></rss>`), "")
+	if err != nil {
+		t.Fatalf("ParseFeed() error = %v", err)
+	}
+
+	tokyo, err := ParseTimeZone("Asia/Tokyo")
+	if err != nil {
+		t.Fatalf("ParseTimeZone() error = %v", err)
+	}
+	opts := ExportOptions{DateFormat: DateRFC3339, TimeZone: tokyo}
+
+	var buf bytes.Buffer
+	if err := exporter.Export(context.Background(), &buf, rss, opts); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("Failed to read CSV: %v", err)
+	}
+	if records[0][3] != "PubDate" || records[0][4] != "PubDateRaw" {
+		t.Fatalf("Headers = %v, want PubDate followed by PubDateRaw", records[0])
+	}
+
+	want := [][2]string{
+		{"2024-01-03T05:04:05+09:00", "Tue, 02 Jan 2024 15:04:05 EST"},
+		{"2024-01-03T17:00:00+09:00", "2024-01-03T08:00:00Z"},
+		{"", "sometime last week"},
+	}
+	for i, w := range want {
+		if got := [2]string{records[i+1][3], records[i+1][4]}; got != w {
+			t.Errorf("row %d dates = %q, want %q", i+1, got, w)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+package services
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+	"rss-feed-to-csv/internal/utils"
+)
+
+// excelDateLayout is a date and time format spreadsheets recognise on import
+const excelDateLayout = "2006-01-02 15:04:05"
+
+// DateFormat selects how date columns are written
+type DateFormat string
+
+const (
+	// DateRaw writes dates exactly as they appear in the feed
+	DateRaw DateFormat = "raw"
+	// DateRFC3339 writes dates such as 2024-01-02T15:04:05Z
+	DateRFC3339 DateFormat = "rfc3339"
+	// DateExcel writes dates such as 2024-01-02 15:04:05
+	DateExcel DateFormat = "excel"
+	// DateUnix writes dates as seconds since the Unix epoch
+	DateUnix DateFormat = "unix"
+	// DateCustom writes dates with a Go reference time layout
+	DateCustom DateFormat = "custom"
+)
+
+// ParseDateFormat validates a date format name and, for DateCustom, its Go
+// layout. An empty name selects DateCustom when a layout is given and DateRaw
+// otherwise.
+func ParseDateFormat(name, layout string) (DateFormat, error) {
+	format := DateFormat(strings.ToLower(name))
+	if format == "" {
+		format = DateRaw
+		if layout != "" {
+			format = DateCustom
+		}
+	}
+
+	switch format {
+	case DateRaw, DateRFC3339, DateExcel, DateUnix:
+		if layout != "" {
+			return "", &errors.ValidationError{
+				Field:   "date_layout",
+				Message: fmt.Sprintf("only allowed with the custom date format, not %q", name),
+			}
+		}
+		return format, nil
+	case DateCustom:
+		// A layout without any reference time element would write the same
+		// text for every date
+		sample := time.Date(1999, 11, 28, 22, 33, 44, 0, time.UTC)
+		if layout == "" || sample.Format(layout) == layout {
+			return "", &errors.ValidationError{
+				Field:   "date_layout",
+				Message: "must be a Go time layout such as 2006-01-02 15:04",
+			}
+		}
+		return format, nil
+	default:
+		return "", &errors.ValidationError{
+			Field:   "date_format",
+			Message: fmt.Sprintf("unsupported format %q: must be raw, rfc3339, excel, unix or custom", name),
+		}
+	}
+}
+
+// ParseTimeZone loads an IANA time zone such as Europe/Berlin. An empty
+// name selects UTC.
+func ParseTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		return time.UTC, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, &errors.ValidationError{
+			Field:   "timezone",
+			Message: fmt.Sprintf("unknown time zone %q", name),
+		}
+	}
+	return location, nil
+}
+
+// formatDate writes a parsed date in the given format and time zone. A zero
+// time, from a missing or unparseable date, is written as an empty string.
+func formatDate(t time.Time, format DateFormat, layout string, location *time.Location) string {
+	if t.IsZero() {
+		return ""
+	}
+	if location != nil {
+		t = t.In(location)
+	}
+	switch format {
+	case DateUnix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case DateExcel:
+		return t.Format(excelDateLayout)
+	case DateCustom:
+		return t.Format(layout)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+// itemPublished returns the parsed publication date of an item, parsing
+// PubDate for items that did not come through the feed parser
+func itemPublished(item *models.Item) time.Time {
+	if !item.PublishedAt.IsZero() {
+		return item.PublishedAt
+	}
+	t, _ := utils.ParseDate(item.PubDate)
+	return t
+}
+
+// channelLastBuild returns the parsed last build date of a channel
+func channelLastBuild(channel *models.Channel) time.Time {
+	if !channel.LastBuildAt.IsZero() {
+		return channel.LastBuildAt
+	}
+	t, _ := utils.ParseDate(channel.LastBuildDate)
+	return t
+}
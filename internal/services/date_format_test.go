@@ -0,0 +1,82 @@
+package services
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		layout  string
+		want    DateFormat
+		wantErr bool
+	}{
+		{"", "", DateRaw, false},
+		{"raw", "", DateRaw, false},
+		{"RFC3339", "", DateRFC3339, false},
+		{"excel", "", DateExcel, false},
+		{"unix", "", DateUnix, false},
+		{"", "02/01/2006", DateCustom, false},
+		{"custom", "2006-01-02 15:04", DateCustom, false},
+		{"custom", "", "", true},
+		{"custom", "no date here", "", true},
+		{"unix", "2006", "", true},
+		{"iso", "", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseDateFormat(tt.name, tt.layout)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseDateFormat(%q, %q) error = %v, wantErr %v", tt.name, tt.layout, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseDateFormat(%q, %q) = %q, want %q", tt.name, tt.layout, got, tt.want)
+		}
+	}
+}
+
+func TestParseTimeZone(t *testing.T) {
+	if location, err := ParseTimeZone(""); err != nil || location != time.UTC {
+		t.Errorf("ParseTimeZone(\"\") = %v, %v, want UTC", location, err)
+	}
+	if location, err := ParseTimeZone("Europe/Berlin"); err != nil || location.String() != "Europe/Berlin" {
+		t.Errorf("ParseTimeZone(Europe/Berlin) = %v, %v", location, err)
+	}
+	if _, err := ParseTimeZone("Mars/Olympus_Mons"); err == nil {
+		t.Error("ParseTimeZone() should reject unknown zones")
+	}
+}
+
+func TestFormatDate(t *testing.T) {
+	date := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+	berlin, err := time.LoadLocation("Europe/Berlin")
+	if err != nil {
+		t.Fatalf("LoadLocation() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		t        time.Time
+		format   DateFormat
+		layout   string
+		location *time.Location
+		want     string
+	}{
+		{"RFC 3339", date, DateRFC3339, "", nil, "2024-01-02T15:04:05Z"},
+		{"RFC 3339 in zone", date, DateRFC3339, "", berlin, "2024-01-02T16:04:05+01:00"},
+		{"Excel", date, DateExcel, "", berlin, "2024-01-02 16:04:05"},
+		{"Unix", date, DateUnix, "", berlin, "1704207845"},
+		{"custom", date, DateCustom, "02.01.2006", nil, "02.01.2024"},
+		{"zero time", time.Time{}, DateRFC3339, "", nil, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := formatDate(tt.t, tt.format, tt.layout, tt.location); got != tt.want {
+				t.Errorf("formatDate() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package services
+
+import (
+	"html"
+	"mime"
+	neturl "net/url"
+	"regexp"
+	"strings"
+)
+
+// maxDiscoveryCandidates bounds how many feed URLs are tried for one page
+const maxDiscoveryCandidates = 8
+
+// feedLinkTypes are the media types of <link rel="alternate"> tags that point at a feed
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+	"application/rdf+xml":   true,
+}
+
+// commonFeedPaths are tried, relative to the site root, when a page does not
+// advertise its feed
+var commonFeedPaths = []string{
+	"/feed",
+	"/rss.xml",
+	"/feed.xml",
+	"/atom.xml",
+	"/index.xml",
+	"/rss",
+	"/feed.json",
+}
+
+var (
+	htmlTagPattern  = regexp.MustCompile(`(?is)<(link|base)\b[^>]*>`)
+	htmlAttrPattern = regexp.MustCompile(`(?s)([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+)
+
+// discoverFeedURLs returns the feed URLs worth trying for an HTML page, best
+// first: feeds the page links to in document order, then the common feed
+// paths of the page's site. Relative links are resolved against the page URL
+// or its <base href>.
+func discoverFeedURLs(page []byte, pageURL string) []string {
+	base, err := neturl.Parse(pageURL)
+	if err != nil {
+		return nil
+	}
+
+	var candidates []string
+	seen := map[string]bool{pageURL: true}
+	add := func(ref *neturl.URL, href string) {
+		resolved, err := ref.Parse(strings.TrimSpace(href))
+		if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+			return
+		}
+		resolved.Fragment = ""
+		candidate := resolved.String()
+		if !seen[candidate] && len(candidates) < maxDiscoveryCandidates {
+			seen[candidate] = true
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	ref := base
+	for _, tag := range htmlTagPattern.FindAllSubmatch(page, -1) {
+		attrs := htmlAttrs(tag[0])
+		if strings.EqualFold(string(tag[1]), "base") {
+			if href, ok := attrs["href"]; ok {
+				if resolved, err := base.Parse(strings.TrimSpace(href)); err == nil {
+					ref = resolved
+				}
+			}
+			continue
+		}
+		if isFeedLink(attrs) {
+			add(ref, attrs["href"])
+		}
+	}
+
+	root := &neturl.URL{Scheme: base.Scheme, Host: base.Host}
+	for _, path := range commonFeedPaths {
+		add(root, path)
+	}
+	return candidates
+}
+
+// isFeedLink reports whether a <link> tag's attributes advertise a feed
+func isFeedLink(attrs map[string]string) bool {
+	if attrs["href"] == "" {
+		return false
+	}
+	alternate := false
+	for _, rel := range strings.Fields(attrs["rel"]) {
+		alternate = alternate || strings.EqualFold(rel, "alternate")
+	}
+	mediaType, _, err := mime.ParseMediaType(attrs["type"])
+	return alternate && err == nil && feedLinkTypes[mediaType]
+}
+
+// htmlAttrs extracts the attributes of a single HTML tag, with names
+// lowercased and values unescaped
+func htmlAttrs(tag []byte) map[string]string {
+	attrs := make(map[string]string)
+	for _, match := range htmlAttrPattern.FindAllSubmatch(tag, -1) {
+		name := strings.ToLower(string(match[1]))
+		if _, ok := attrs[name]; ok {
+			continue
+		}
+		value := string(match[2])
+		if value[0] == '"' || value[0] == '\'' {
+			value = value[1 : len(value)-1]
+		}
+		attrs[name] = html.UnescapeString(value)
+	}
+	return attrs
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestDiscoverFeedURLs(t *testing.T) {
+	commonPaths := func(root string) []string {
+		urls := make([]string, len(commonFeedPaths))
+		for i, path := range commonFeedPaths {
+			urls[i] = root + path
+		}
+		return urls
+	}
+
+	tests := []struct {
+		name    string
+		page    string
+		pageURL string
+		want    []string
+	}{
+		{
+			name: "linked feeds in document order",
+			page: `<!DOCTYPE html><html><head>
+				<link rel="stylesheet" href="/style.css">
+				<link rel="alternate" type="application/atom+xml" href="/atom">
+				<LINK REL="Alternate" TYPE="application/rss+xml; charset=utf-8" HREF='comments/feed?a=1&amp;b=2'>
+				<link rel="alternate" type="text/html" href="/fr/">
+				<link rel="alternate" type="application/feed+json" href="https://cdn.example.com/feed.json">
+			</head></html>`,
+			pageURL: "https://example.com/blog/",
+			want: append([]string{
+				"https://example.com/atom",
+				"https://example.com/blog/comments/feed?a=1&b=2",
+				"https://cdn.example.com/feed.json",
+			}, commonPaths("https://example.com")...)[:maxDiscoveryCandidates],
+		},
+		{
+			name:    "base href",
+			page:    `<html><head><base href="https://example.com/news/"><link rel="alternate" type="application/rss+xml" href="rss.xml"></head></html>`,
+			pageURL: "https://example.com/",
+			want: append([]string{
+				"https://example.com/news/rss.xml",
+			}, commonPaths("https://example.com")...),
+		},
+		{
+			name:    "common paths only",
+			page:    `<html><head><title>Blog</title></head></html>`,
+			pageURL: "http://example.com:8080/posts/1",
+			want:    commonPaths("http://example.com:8080"),
+		},
+		{
+			name:    "non-HTTP and duplicate links are skipped",
+			page:    `<link rel="alternate" type="application/rss+xml" href="javascript:alert(1)"><link rel="alternate" type="application/rss+xml" href="/feed">`,
+			pageURL: "https://example.com/",
+			want:    commonPaths("https://example.com"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := discoverFeedURLs([]byte(tt.page), tt.pageURL)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("discoverFeedURLs() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
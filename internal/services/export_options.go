@@ -0,0 +1,146 @@
+package services
+
+import (
+	"time"
+
+	"rss-feed-to-csv/internal/models"
+	"rss-feed-to-csv/internal/utils"
+)
+
+// ExportMode selects what an export contains
+type ExportMode string
+
+const (
+	// ModeItems exports one row per feed item
+	ModeItems ExportMode = "items"
+	// ModeChannel exports a single summary row describing the feed itself
+	ModeChannel ExportMode = "channel"
+)
+
+// ExportOptions controls how a feed is exported
+type ExportOptions struct {
+	// SanitizeHTML strips HTML from description and content fields
+	SanitizeHTML bool
+	// IncludeFeedColumns prepends FeedTitle, FeedLink and FeedLanguage columns to every row
+	IncludeFeedColumns bool
+	// Columns selects and orders the exported columns; empty means every item column
+	Columns []Column
+	// Mode selects between item rows and a channel summary; empty means ModeItems
+	Mode ExportMode
+	// MultiValueSeparator joins multi-valued fields; empty means DefaultMultiValueSeparator
+	MultiValueSeparator string
+	// CSV controls the delimiter, BOM, line endings and quoting of CSV output
+	CSV CSVDialect
+	// FormulaProtection neutralizes CSV cells that spreadsheets would evaluate
+	// as formulas; empty means FormulaEscape
+	FormulaProtection FormulaProtection
+	// DateFormat selects how date columns are written; empty means DateRaw
+	DateFormat DateFormat
+	// DateLayout is the Go time layout used with DateCustom
+	DateLayout string
+	// TimeZone is the zone reformatted dates are converted to; nil means UTC
+	TimeZone *time.Location
+	// BatchColumns prepends FeedURL and FeedTitle columns naming each item's
+	// feed, in place of the feed columns
+	BatchColumns bool
+	// Summary, when set, is written after the items as a per-feed report
+	Summary []FeedReport
+	// Sort orders the items; empty keeps the feed's order
+	Sort []SortKey
+	// Offset skips that many items after sorting
+	Offset int
+	// Limit caps the number of items exported; zero means no limit
+	Limit int
+}
+
+// separator returns the configured multi-value separator or the default
+func (o ExportOptions) separator() string {
+	if o.MultiValueSeparator == "" {
+		return DefaultMultiValueSeparator
+	}
+	return o.MultiValueSeparator
+}
+
+// formulaProtection returns the configured formula protection or the default
+func (o ExportOptions) formulaProtection() FormulaProtection {
+	if o.FormulaProtection == "" {
+		return FormulaEscape
+	}
+	return o.FormulaProtection
+}
+
+// dateFormat returns the configured date format or the default
+func (o ExportOptions) dateFormat() DateFormat {
+	if o.DateFormat == "" {
+		return DateRaw
+	}
+	return o.DateFormat
+}
+
+// columns returns the columns to export, including feed columns if requested.
+// When dates are reformatted the default columns also keep the raw PubDate.
+func (o ExportOptions) columns() []Column {
+	if o.Mode == ModeChannel {
+		return channelColumns
+	}
+
+	selected := o.Columns
+	if len(selected) == 0 {
+		selected = defaultItemColumns(o.dateFormat() != DateRaw)
+	}
+	prefix := feedColumns
+	switch {
+	case o.BatchColumns:
+		prefix = batchColumns
+	case !o.IncludeFeedColumns:
+		return selected
+	}
+	columns := make([]Column, 0, len(prefix)+len(selected))
+	columns = append(columns, prefix...)
+	return append(columns, selected...)
+}
+
+// rows returns the items to export, one per row, sorted and then cut to the
+// offset and limit. A channel summary has a single row whose columns only
+// read channel metadata, so its item is nil.
+func (o ExportOptions) rows(rss *models.RSS) []*models.Item {
+	if o.Mode == ModeChannel {
+		return []*models.Item{nil}
+	}
+	rows := make([]*models.Item, len(rss.Channel.Items))
+	for i := range rss.Channel.Items {
+		rows[i] = &rss.Channel.Items[i]
+	}
+	if len(o.Sort) > 0 {
+		sortItems(rows, &rss.Channel, o.Sort)
+	}
+	rows = rows[min(o.Offset, len(rows)):]
+	return rows[:o.RowCount(rss)]
+}
+
+// RowCount returns the number of rows selected for an export of rss, after
+// the offset and limit are applied. A CSV export with FormulaReject writes
+// fewer when it drops rows containing formulas.
+func (o ExportOptions) RowCount(rss *models.RSS) int {
+	if o.Mode == ModeChannel {
+		return 1
+	}
+	count := max(len(rss.Channel.Items)-o.Offset, 0)
+	if o.Limit > 0 && o.Limit < count {
+		count = o.Limit
+	}
+	return count
+}
+
+// cellValue renders a column for an item, reformatting dates and applying
+// HTML sanitization if requested
+func (o ExportOptions) cellValue(sanitizer *utils.HTMLSanitizer, column Column, item *models.Item, channel *models.Channel) string {
+	if column.date != nil && o.dateFormat() != DateRaw {
+		return formatDate(column.date(item, channel), o.dateFormat(), o.DateLayout, o.TimeZone)
+	}
+	value := column.Value(item, channel, o.separator())
+	if column.HTML && o.SanitizeHTML {
+		value = sanitizer.StripHTML(value)
+	}
+	return value
+}
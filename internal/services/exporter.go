@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+)
+
+// Exporter writes a parsed feed to an output format
+type Exporter interface {
+	// ContentType is the media type of the exporter's output
+	ContentType() string
+	// FileExtension is the extension, without a dot, used for downloads
+	FileExtension() string
+	// Export writes the feed to w
+	Export(ctx context.Context, w io.Writer, rss *models.RSS, opts ExportOptions) error
+}
+
+// Compile-time checks that every exporter satisfies the interface
+var (
+	_ Exporter = (*CSVExporter)(nil)
+	_ Exporter = (*XLSXExporter)(nil)
+	_ Exporter = (*JSONExporter)(nil)
+	_ Exporter = (*NDJSONExporter)(nil)
+)
+
+// ExporterRegistry maps format names to exporters. The first registered
+// format is the default when a request expresses no preference.
+type ExporterRegistry struct {
+	exporters map[string]Exporter
+	names     []string
+}
+
+// NewExporterRegistry creates an empty exporter registry
+func NewExporterRegistry() *ExporterRegistry {
+	return &ExporterRegistry{
+		exporters: make(map[string]Exporter),
+	}
+}
+
+// DefaultExporterRegistry returns a registry with every built-in format, CSV first
+func DefaultExporterRegistry() *ExporterRegistry {
+	registry := NewExporterRegistry()
+	registry.Register("csv", NewCSVExporter())
+	registry.Register("xlsx", NewXLSXExporter())
+	registry.Register("json", NewJSONExporter())
+	registry.Register("ndjson", NewNDJSONExporter())
+	return registry
+}
+
+// Register adds or replaces the exporter for a format name
+func (r *ExporterRegistry) Register(name string, exporter Exporter) {
+	name = strings.ToLower(name)
+	if _, exists := r.exporters[name]; !exists {
+		r.names = append(r.names, name)
+	}
+	r.exporters[name] = exporter
+}
+
+// Get returns the exporter registered for a format name
+func (r *ExporterRegistry) Get(name string) (Exporter, bool) {
+	exporter, ok := r.exporters[strings.ToLower(name)]
+	return exporter, ok
+}
+
+// Formats returns the registered format names in registration order
+func (r *ExporterRegistry) Formats() []string {
+	return append([]string(nil), r.names...)
+}
+
+// Negotiate selects an exporter from an explicit format name or, when that is
+// empty, from an HTTP Accept header. It returns the chosen format name.
+func (r *ExporterRegistry) Negotiate(format, accept string) (string, Exporter, error) {
+	if format != "" {
+		if exporter, ok := r.Get(format); ok {
+			return strings.ToLower(format), exporter, nil
+		}
+		return "", nil, fmt.Errorf("%w: %q (supported: %s)", errors.ErrUnsupportedFormat, format, strings.Join(r.names, ", "))
+	}
+
+	if len(r.names) == 0 {
+		return "", nil, errors.ErrUnsupportedFormat
+	}
+	if strings.TrimSpace(accept) == "" {
+		return r.names[0], r.exporters[r.names[0]], nil
+	}
+
+	ranges := parseAccept(accept)
+	for _, accepted := range ranges {
+		if accepted.q == 0 {
+			break
+		}
+		for _, name := range r.names {
+			contentType := r.exporters[name].ContentType()
+			if mediaRangeMatches(accepted.mediaRange, contentType) && !excluded(ranges, accepted, contentType) {
+				return name, r.exporters[name], nil
+			}
+		}
+	}
+	return "", nil, fmt.Errorf("%w: no format matches Accept %q", errors.ErrUnsupportedFormat, accept)
+}
+
+// acceptRange is a media range of an Accept header with its quality value
+type acceptRange struct {
+	mediaRange string
+	q          float64
+}
+
+// parseAccept returns the media ranges of an Accept header ordered by
+// preference. Ranges with q=0 come last; they exclude the types they match.
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if parsed, err := strconv.ParseFloat(value, 64); err == nil {
+				q = max(parsed, 0)
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType, q})
+	}
+
+	sort.SliceStable(ranges, func(i, j int) bool {
+		return ranges[i].q > ranges[j].q
+	})
+	return ranges
+}
+
+// excluded reports whether a q=0 range refuses a content type that the
+// accepted range matched. Only an exclusion at least as specific as the
+// accepted range counts, so "text/*;q=0, text/csv" still allows CSV.
+func excluded(ranges []acceptRange, accepted acceptRange, contentType string) bool {
+	for _, r := range ranges {
+		if r.q == 0 && mediaRangeMatches(r.mediaRange, contentType) &&
+			rangeSpecificity(r.mediaRange) >= rangeSpecificity(accepted.mediaRange) {
+			return true
+		}
+	}
+	return false
+}
+
+// rangeSpecificity ranks */* below type/* below a full media type
+func rangeSpecificity(mediaRange string) int {
+	switch {
+	case mediaRange == "*/*":
+		return 0
+	case strings.HasSuffix(mediaRange, "/*"):
+		return 1
+	}
+	return 2
+}
+
+// mediaRangeMatches reports whether a media range such as text/* accepts a content type
+func mediaRangeMatches(mediaRange, contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	if mediaRange == "*/*" || mediaRange == mediaType {
+		return true
+	}
+	if prefix, ok := strings.CutSuffix(mediaRange, "/*"); ok {
+		return strings.HasPrefix(mediaType, prefix+"/")
+	}
+	return false
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	stderrors "errors"
+	"testing"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+func TestExporterRegistry_Negotiate(t *testing.T) {
+	registry := DefaultExporterRegistry()
+
+	tests := []struct {
+		name       string
+		format     string
+		accept     string
+		wantFormat string
+		wantErr    bool
+	}{
+		{name: "no preference defaults to csv", wantFormat: "csv"},
+		{name: "explicit format", format: "xlsx", wantFormat: "xlsx"},
+		{name: "explicit format is case insensitive", format: "JSON", wantFormat: "json"},
+		{name: "explicit format wins over Accept", format: "ndjson", accept: "text/csv", wantFormat: "ndjson"},
+		{name: "unknown format", format: "pdf", wantErr: true},
+		{name: "Accept exact match", accept: "application/x-ndjson", wantFormat: "ndjson"},
+		{name: "Accept with quality values", accept: "text/csv;q=0.5, application/json", wantFormat: "json"},
+		{name: "Accept wildcard subtype", accept: "application/*", wantFormat: "xlsx"},
+		{name: "Accept browser default", accept: "text/html,application/xhtml+xml,*/*;q=0.8", wantFormat: "csv"},
+		{name: "Accept q=0 excludes format", accept: "text/csv;q=0, application/json;q=0.1", wantFormat: "json"},
+		{name: "Accept q=0 excludes format from wildcard", accept: "*/*, text/csv;q=0", wantFormat: "xlsx"},
+		{name: "Accept q=0 on subtype wildcard excludes formats", accept: "application/*;q=0, */*;q=0.5", wantFormat: "csv"},
+		{name: "Accept specific range overrides q=0 wildcard", accept: "text/*;q=0, text/csv", wantFormat: "csv"},
+		{name: "Accept every format excluded", accept: "*/*;q=0", wantErr: true},
+		{name: "Accept unsupported", accept: "application/pdf", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			format, exporter, err := registry.Negotiate(tt.format, tt.accept)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Negotiate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				if !stderrors.Is(err, errors.ErrUnsupportedFormat) {
+					t.Errorf("Negotiate() error = %v, want ErrUnsupportedFormat", err)
+				}
+				return
+			}
+			if format != tt.wantFormat {
+				t.Errorf("Negotiate() format = %q, want %q", format, tt.wantFormat)
+			}
+			if want, _ := registry.Get(tt.wantFormat); exporter != want {
+				t.Errorf("Negotiate() exporter = %T, want %T", exporter, want)
+			}
+		})
+	}
+}
+
+func TestExporterRegistry_Register(t *testing.T) {
+	registry := NewExporterRegistry()
+	if _, _, err := registry.Negotiate("", ""); err == nil {
+		t.Error("Negotiate() on empty registry should fail")
+	}
+
+	csvExporter := NewCSVExporter()
+	registry.Register("TSV", csvExporter)
+	registry.Register("json", NewJSONExporter())
+	registry.Register("tsv", csvExporter)
+
+	if got := registry.Formats(); len(got) != 2 || got[0] != "tsv" || got[1] != "json" {
+		t.Errorf("Formats() = %v, want [tsv json]", got)
+	}
+	if exporter, ok := registry.Get("tsv"); !ok || exporter != csvExporter {
+		t.Error("Get(tsv) should return the registered exporter")
+	}
+}
+
+func TestExporters_Metadata(t *testing.T) {
+	tests := []struct {
+		exporter        Exporter
+		wantContentType string
+		wantExtension   string
+	}{
+		{NewCSVExporter(), "text/csv", "csv"},
+		{NewXLSXExporter(), "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", "xlsx"},
+		{NewJSONExporter(), "application/json", "json"},
+		{NewNDJSONExporter(), "application/x-ndjson", "ndjson"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.exporter.ContentType(); got != tt.wantContentType {
+			t.Errorf("%T.ContentType() = %q, want %q", tt.exporter, got, tt.wantContentType)
+		}
+		if got := tt.exporter.FileExtension(); got != tt.wantExtension {
+			t.Errorf("%T.FileExtension() = %q, want %q", tt.exporter, got, tt.wantExtension)
+		}
+	}
+}
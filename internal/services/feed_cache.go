@@ -0,0 +1,301 @@
+package services
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache backends accepted by NewFeedCache
+const (
+	CacheBackendNone   = "none"
+	CacheBackendMemory = "memory"
+	CacheBackendDisk   = "disk"
+)
+
+// CachedFeed is a fetched feed body together with the validators needed to
+// revalidate it with a conditional GET
+type CachedFeed struct {
+	Body         []byte    `json:"body"`
+	ContentType  string    `json:"contentType"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+	// ExpiresAt is when the entry stops being served without revalidation
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// FeedCache stores fetched feeds keyed by URL
+type FeedCache interface {
+	Get(url string) (CachedFeed, bool)
+	Set(url string, feed CachedFeed) error
+}
+
+// CacheLimits bounds how much a feed cache keeps
+type CacheLimits struct {
+	// MaxEntries is the most feeds kept; zero or less means one
+	MaxEntries int
+	// MaxBytes is the most cached data kept, counting feed bodies in memory
+	// and file sizes on disk; zero or less means no limit
+	MaxBytes int64
+	// Retention is how long the disk backend keeps an entry after it
+	// expires, so it can still be revalidated with a conditional GET
+	Retention time.Duration
+}
+
+// NewFeedCache creates a cache for the named backend. The memory backend
+// keeps feeds in process; the disk backend stores them under dir. Both
+// evict entries beyond the limits. The none backend returns a nil cache,
+// which disables caching.
+func NewFeedCache(backend, dir string, limits CacheLimits) (FeedCache, error) {
+	switch strings.ToLower(backend) {
+	case "", CacheBackendNone:
+		return nil, nil
+	case CacheBackendMemory:
+		return NewMemoryFeedCache(limits), nil
+	case CacheBackendDisk:
+		return NewDiskFeedCache(dir, limits)
+	default:
+		return nil, fmt.Errorf("unknown cache backend %q: must be none, memory or disk", backend)
+	}
+}
+
+// MemoryFeedCache is an in-memory least-recently-used feed cache
+type MemoryFeedCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	maxBytes   int64
+	size       int64
+	order      *list.List
+	entries    map[string]*list.Element
+}
+
+// memoryEntry is the value stored in MemoryFeedCache's recency list
+type memoryEntry struct {
+	url  string
+	feed CachedFeed
+}
+
+// NewMemoryFeedCache creates an LRU cache holding at most limits.MaxEntries
+// feeds whose bodies total at most limits.MaxBytes
+func NewMemoryFeedCache(limits CacheLimits) *MemoryFeedCache {
+	return &MemoryFeedCache{
+		maxEntries: max(limits.MaxEntries, 1),
+		maxBytes:   limits.MaxBytes,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached feed for a URL and marks it as recently used
+func (c *MemoryFeedCache) Get(url string) (CachedFeed, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[url]
+	if !ok {
+		return CachedFeed{}, false
+	}
+	c.order.MoveToFront(element)
+	return element.Value.(*memoryEntry).feed, true
+}
+
+// Set stores a feed, evicting the least recently used entries while the
+// cache is over its limits. A feed larger than the whole cache is not stored.
+func (c *MemoryFeedCache) Set(url string, feed CachedFeed) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[url]; ok {
+		c.remove(element)
+	}
+	if c.maxBytes > 0 && int64(len(feed.Body)) > c.maxBytes {
+		return nil
+	}
+
+	c.entries[url] = c.order.PushFront(&memoryEntry{url: url, feed: feed})
+	c.size += int64(len(feed.Body))
+	for c.order.Len() > c.maxEntries || (c.maxBytes > 0 && c.size > c.maxBytes) {
+		c.remove(c.order.Back())
+	}
+	return nil
+}
+
+// remove drops an entry. The caller must hold c.mu.
+func (c *MemoryFeedCache) remove(element *list.Element) {
+	entry := c.order.Remove(element).(*memoryEntry)
+	delete(c.entries, entry.url)
+	c.size -= int64(len(entry.feed.Body))
+}
+
+// DiskFeedCache stores each feed as a JSON file in a directory, so cached
+// feeds survive restarts. A file's modification time is set to the entry's
+// expiry, which is what pruning and eviction go by.
+type DiskFeedCache struct {
+	dir    string
+	limits CacheLimits
+	now    func() time.Time
+	// mu serializes pruning so concurrent writes do not race to evict
+	mu sync.Mutex
+}
+
+// NewDiskFeedCache creates a disk cache in dir, creating the directory if
+// needed and pruning entries left over beyond the limits
+func NewDiskFeedCache(dir string, limits CacheLimits) (*DiskFeedCache, error) {
+	if dir == "" {
+		return nil, fmt.Errorf("cache directory must be set for the disk backend")
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create cache directory: %w", err)
+	}
+	limits.MaxEntries = max(limits.MaxEntries, 1)
+	c := &DiskFeedCache{dir: dir, limits: limits, now: time.Now}
+	if err := c.prune(""); err != nil {
+		return nil, fmt.Errorf("failed to prune cache directory: %w", err)
+	}
+	return c, nil
+}
+
+// Get reads the cached feed for a URL. Missing or unreadable entries are misses.
+func (c *DiskFeedCache) Get(url string) (CachedFeed, bool) {
+	data, err := os.ReadFile(c.path(url))
+	if err != nil {
+		return CachedFeed{}, false
+	}
+	var feed CachedFeed
+	if err := json.Unmarshal(data, &feed); err != nil {
+		return CachedFeed{}, false
+	}
+	return feed, true
+}
+
+// Set writes a feed to disk and prunes the cache back within its limits.
+// The file is written under a temporary name and renamed so concurrent
+// readers never see a partial entry. A feed larger than the whole cache is
+// not stored.
+func (c *DiskFeedCache) Set(url string, feed CachedFeed) error {
+	data, err := json.Marshal(feed)
+	if err != nil {
+		return err
+	}
+	if c.limits.MaxBytes > 0 && int64(len(data)) > c.limits.MaxBytes {
+		return nil
+	}
+
+	tmp, err := os.CreateTemp(c.dir, "feed-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chtimes(tmp.Name(), feed.ExpiresAt, feed.ExpiresAt); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), c.path(url)); err != nil {
+		return err
+	}
+	return c.prune(c.path(url))
+}
+
+// prune deletes entries that expired more than the retention period ago,
+// then the entries closest to expiry until the cache is within its limits.
+// The entry at keep, which was just written, is evicted last.
+func (c *DiskFeedCache) prune(keep string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dirEntries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return err
+	}
+
+	type cacheFile struct {
+		path      string
+		size      int64
+		expiresAt time.Time
+	}
+	var files []cacheFile
+	var total int64
+	cutoff := c.now().Add(-c.limits.Retention)
+	for _, entry := range dirEntries {
+		if entry.IsDir() || filepath.Ext(entry.Name()) != ".json" {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			// Deleted by a concurrent writer
+			continue
+		}
+		file := cacheFile{path: filepath.Join(c.dir, entry.Name()), size: info.Size(), expiresAt: info.ModTime()}
+		if file.path != keep && file.expiresAt.Before(cutoff) {
+			if err := os.Remove(file.path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		files = append(files, file)
+		total += file.size
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if (files[i].path == keep) != (files[j].path == keep) {
+			return files[j].path == keep
+		}
+		return files[i].expiresAt.Before(files[j].expiresAt)
+	})
+	for len(files) > c.limits.MaxEntries || (c.limits.MaxBytes > 0 && total > c.limits.MaxBytes) {
+		if err := os.Remove(files[0].path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= files[0].size
+		files = files[1:]
+	}
+	return nil
+}
+
+// path returns the file used for a URL
+func (c *DiskFeedCache) path(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+// cacheExpiry works out how long a response may be served from cache. A
+// Cache-Control max-age overrides the default TTL, no-cache forces
+// revalidation on every request and no-store disables caching entirely.
+func cacheExpiry(header http.Header, now time.Time, ttl time.Duration) (time.Time, bool) {
+	noCache := false
+	for _, directive := range strings.Split(header.Get("Cache-Control"), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(directive), "=")
+		switch strings.ToLower(name) {
+		case "no-store":
+			return time.Time{}, false
+		case "no-cache":
+			noCache = true
+		case "max-age":
+			if seconds, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				ttl = time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	if noCache {
+		return now, true
+	}
+	return now.Add(ttl), true
+}
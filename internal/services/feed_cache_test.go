@@ -0,0 +1,234 @@
+package services
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestMemoryFeedCache_LRU(t *testing.T) {
+	cache := NewMemoryFeedCache(CacheLimits{MaxEntries: 2})
+	cache.Set("a", CachedFeed{ETag: "a"})
+	cache.Set("b", CachedFeed{ETag: "b"})
+
+	// Reading a makes b the least recently used entry
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Get(a) missed")
+	}
+	cache.Set("c", CachedFeed{ETag: "c"})
+
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Get(b) hit, want evicted")
+	}
+	for _, url := range []string{"a", "c"} {
+		if feed, ok := cache.Get(url); !ok || feed.ETag != url {
+			t.Errorf("Get(%s) = %+v, %v, want cached entry", url, feed, ok)
+		}
+	}
+
+	cache.Set("a", CachedFeed{ETag: "a2"})
+	if feed, _ := cache.Get("a"); feed.ETag != "a2" {
+		t.Errorf("Get(a) ETag = %q, want updated entry", feed.ETag)
+	}
+}
+
+func TestMemoryFeedCache_MaxBytes(t *testing.T) {
+	cache := NewMemoryFeedCache(CacheLimits{MaxEntries: 10, MaxBytes: 10})
+	cache.Set("a", CachedFeed{Body: []byte("aaaa")})
+	cache.Set("b", CachedFeed{Body: []byte("bbbb")})
+	// Replacing an entry counts only its new size
+	cache.Set("b", CachedFeed{Body: []byte("bbb")})
+	if _, ok := cache.Get("a"); !ok {
+		t.Fatal("Get(a) missed while within the byte limit")
+	}
+
+	// c pushes the total past 10 bytes, evicting b as least recently used
+	cache.Set("c", CachedFeed{Body: []byte("cccc")})
+	if _, ok := cache.Get("b"); ok {
+		t.Error("Get(b) hit, want evicted")
+	}
+	for _, url := range []string{"a", "c"} {
+		if _, ok := cache.Get(url); !ok {
+			t.Errorf("Get(%s) missed", url)
+		}
+	}
+
+	cache.Set("huge", CachedFeed{Body: make([]byte, 11)})
+	if _, ok := cache.Get("huge"); ok {
+		t.Error("Get(huge) hit, want feeds over the byte limit skipped")
+	}
+	if _, ok := cache.Get("a"); !ok {
+		t.Error("Get(a) missed, a feed over the limit should not evict others")
+	}
+}
+
+func TestDiskFeedCache_Prune(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache, err := NewDiskFeedCache(t.TempDir(), CacheLimits{MaxEntries: 2, Retention: time.Hour})
+	if err != nil {
+		t.Fatalf("NewDiskFeedCache() error = %v", err)
+	}
+	cache.now = func() time.Time { return now }
+
+	set := func(url string, expiresAt time.Time) {
+		t.Helper()
+		if err := cache.Set(url, CachedFeed{Body: []byte(url), ExpiresAt: expiresAt}); err != nil {
+			t.Fatalf("Set(%s) error = %v", url, err)
+		}
+	}
+	cached := func(url string) bool {
+		_, ok := cache.Get(url)
+		return ok
+	}
+
+	set("stale", now.Add(-2*time.Hour))
+	if !cached("stale") {
+		t.Fatal("the entry just written was pruned")
+	}
+
+	// The next write prunes stale, which expired more than the retention ago
+	set("revalidate", now.Add(-time.Minute))
+	set("fresh", now.Add(time.Hour))
+	if cached("stale") {
+		t.Error("Get(stale) hit, want pruned after the retention period")
+	}
+	if !cached("revalidate") || !cached("fresh") {
+		t.Error("entries within the retention period were pruned")
+	}
+
+	// Over MaxEntries the entry closest to expiry goes, never the new one
+	set("expiring", now)
+	if cached("revalidate") {
+		t.Error("Get(revalidate) hit, want evicted as closest to expiry")
+	}
+	if !cached("fresh") || !cached("expiring") {
+		t.Error("Set() evicted the wrong entries")
+	}
+}
+
+func TestDiskFeedCache_MaxBytes(t *testing.T) {
+	now := time.Now()
+	probe, err := json.Marshal(CachedFeed{Body: make([]byte, 100), ExpiresAt: now})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Room for two entries but not three
+	cache, err := NewDiskFeedCache(t.TempDir(), CacheLimits{MaxEntries: 10, MaxBytes: int64(len(probe))*3 - 1, Retention: time.Hour})
+	if err != nil {
+		t.Fatalf("NewDiskFeedCache() error = %v", err)
+	}
+
+	for i, url := range []string{"a", "b", "c"} {
+		if err := cache.Set(url, CachedFeed{Body: make([]byte, 100), ExpiresAt: now.Add(time.Duration(i) * time.Minute)}); err != nil {
+			t.Fatalf("Set(%s) error = %v", url, err)
+		}
+	}
+	if _, ok := cache.Get("a"); ok {
+		t.Error("Get(a) hit, want evicted by the byte limit")
+	}
+	for _, url := range []string{"b", "c"} {
+		if _, ok := cache.Get(url); !ok {
+			t.Errorf("Get(%s) missed", url)
+		}
+	}
+
+	if err := cache.Set("huge", CachedFeed{Body: make([]byte, 1000)}); err != nil {
+		t.Fatalf("Set(huge) error = %v", err)
+	}
+	if _, ok := cache.Get("huge"); ok {
+		t.Error("Get(huge) hit, want feeds over the byte limit skipped")
+	}
+}
+
+func TestDiskFeedCache(t *testing.T) {
+	dir := t.TempDir()
+	cache, err := NewDiskFeedCache(dir, CacheLimits{MaxEntries: 10, Retention: 100 * 365 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("NewDiskFeedCache() error = %v", err)
+	}
+
+	if _, ok := cache.Get("https://example.com/feed"); ok {
+		t.Error("Get() hit on empty cache")
+	}
+
+	want := CachedFeed{
+		Body:         []byte("<rss/>"),
+		ContentType:  "application/rss+xml",
+		ETag:         `"v1"`,
+		LastModified: "Mon, 02 Jan 2006 15:04:05 GMT",
+		FetchedAt:    time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC),
+		ExpiresAt:    time.Date(2024, 1, 1, 0, 5, 0, 0, time.UTC),
+	}
+	if err := cache.Set("https://example.com/feed", want); err != nil {
+		t.Fatalf("Set() error = %v", err)
+	}
+
+	// A second cache over the same directory sees the entry, as after a restart
+	reopened, err := NewDiskFeedCache(dir, CacheLimits{MaxEntries: 10, Retention: 100 * 365 * 24 * time.Hour})
+	if err != nil {
+		t.Fatalf("NewDiskFeedCache() error = %v", err)
+	}
+	got, ok := reopened.Get("https://example.com/feed")
+	if !ok {
+		t.Fatal("Get() missed after Set()")
+	}
+	if string(got.Body) != string(want.Body) || got.ContentType != want.ContentType || got.ETag != want.ETag ||
+		got.LastModified != want.LastModified || !got.FetchedAt.Equal(want.FetchedAt) || !got.ExpiresAt.Equal(want.ExpiresAt) {
+		t.Errorf("Get() = %+v, want %+v", got, want)
+	}
+}
+
+func TestNewFeedCache(t *testing.T) {
+	tests := []struct {
+		backend string
+		wantNil bool
+		wantErr bool
+	}{
+		{backend: "", wantNil: true},
+		{backend: "none", wantNil: true},
+		{backend: "memory"},
+		{backend: "DISK"},
+		{backend: "redis", wantNil: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		cache, err := NewFeedCache(tt.backend, t.TempDir(), CacheLimits{MaxEntries: 10})
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NewFeedCache(%q) error = %v, wantErr %v", tt.backend, err, tt.wantErr)
+		}
+		if (cache == nil) != tt.wantNil {
+			t.Errorf("NewFeedCache(%q) = %v, want nil %v", tt.backend, cache, tt.wantNil)
+		}
+	}
+}
+
+func TestCacheExpiry(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	ttl := 5 * time.Minute
+
+	tests := []struct {
+		cacheControl string
+		want         time.Time
+		wantOK       bool
+	}{
+		{"", now.Add(ttl), true},
+		{"public, max-age=60", now.Add(time.Minute), true},
+		{"max-age=0", now, true},
+		{"no-cache", now, true},
+		{"max-age=60, no-cache", now, true},
+		{"private, no-store", time.Time{}, false},
+		{"max-age=invalid", now.Add(ttl), true},
+	}
+
+	for _, tt := range tests {
+		header := http.Header{}
+		if tt.cacheControl != "" {
+			header.Set("Cache-Control", tt.cacheControl)
+		}
+		got, ok := cacheExpiry(header, now, ttl)
+		if !got.Equal(tt.want) || ok != tt.wantOK {
+			t.Errorf("cacheExpiry(%q) = %v, %v, want %v, %v", tt.cacheControl, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
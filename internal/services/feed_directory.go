@@ -0,0 +1,183 @@
+package services
+
+import (
+	"container/list"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"rss-feed-to-csv/internal/models"
+)
+
+// FeedDirectory keeps track of the feeds the server knows about: feeds saved
+// from an OPML import, and the feeds most recently exported. Saved feeds
+// are written to an OPML file when a path is set; exported feeds are only
+// remembered in memory.
+type FeedDirectory struct {
+	mu       sync.Mutex
+	path     string
+	maxKnown int
+	saved    map[string]models.OPMLFeed
+	order    *list.List
+	known    map[string]*list.Element
+}
+
+// NewFeedDirectory creates a feed directory remembering at most maxKnown
+// exported feeds. When path is set the saved feeds are loaded from the OPML
+// file there, if it exists, and every save rewrites it.
+func NewFeedDirectory(path string, maxKnown int) (*FeedDirectory, error) {
+	d := &FeedDirectory{
+		path:     path,
+		maxKnown: maxKnown,
+		saved:    make(map[string]models.OPMLFeed),
+		order:    list.New(),
+		known:    make(map[string]*list.Element),
+	}
+	if path == "" {
+		return d, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return d, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read feeds file: %w", err)
+	}
+	var opml models.OPML
+	if err := xml.Unmarshal(data, &opml); err != nil {
+		return nil, fmt.Errorf("invalid feeds file: %w", err)
+	}
+	for _, feed := range opml.Feeds() {
+		d.saved[feed.URL] = feed
+	}
+	return d, nil
+}
+
+// Record remembers a feed that was exported. Details missing from a saved
+// feed are filled in, since feed readers often export outlines without a
+// title or site URL.
+func (d *FeedDirectory) Record(feed models.OPMLFeed) {
+	if feed.URL == "" {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if saved, ok := d.saved[feed.URL]; ok {
+		if saved.Title == "" {
+			saved.Title = feed.Title
+		}
+		if saved.SiteURL == "" {
+			saved.SiteURL = feed.SiteURL
+		}
+		d.saved[feed.URL] = saved
+		return
+	}
+	if d.maxKnown < 1 {
+		return
+	}
+
+	if element, ok := d.known[feed.URL]; ok {
+		element.Value = feed
+		d.order.MoveToFront(element)
+		return
+	}
+	d.known[feed.URL] = d.order.PushFront(feed)
+	for d.order.Len() > d.maxKnown {
+		oldest := d.order.Back()
+		d.order.Remove(oldest)
+		delete(d.known, oldest.Value.(models.OPMLFeed).URL)
+	}
+}
+
+// Save adds feeds to the saved list, replacing saved feeds with the same
+// URL, and rewrites the feeds file
+func (d *FeedDirectory) Save(feeds []models.OPMLFeed) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	for _, feed := range feeds {
+		d.saved[feed.URL] = feed
+		if element, ok := d.known[feed.URL]; ok {
+			d.order.Remove(element)
+			delete(d.known, feed.URL)
+		}
+	}
+	if d.path == "" {
+		return nil
+	}
+	return d.write()
+}
+
+// Feeds returns the saved feeds and, unless savedOnly is set, the recently
+// exported ones, ordered by title and then URL
+func (d *FeedDirectory) Feeds(savedOnly bool) []models.OPMLFeed {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	feeds := make([]models.OPMLFeed, 0, len(d.saved)+len(d.known))
+	for _, feed := range d.saved {
+		feeds = append(feeds, feed)
+	}
+	if !savedOnly {
+		for element := d.order.Front(); element != nil; element = element.Next() {
+			feeds = append(feeds, element.Value.(models.OPMLFeed))
+		}
+	}
+	sortFeeds(feeds)
+	return feeds
+}
+
+// write saves the saved feeds to the feeds file. The file is written under a
+// temporary name and renamed so a crash never leaves a partial list behind.
+// The caller must hold d.mu.
+func (d *FeedDirectory) write() error {
+	feeds := make([]models.OPMLFeed, 0, len(d.saved))
+	for _, feed := range d.saved {
+		feeds = append(feeds, feed)
+	}
+	sortFeeds(feeds)
+
+	data, err := xml.MarshalIndent(models.NewOPML("Saved feeds", feeds), "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(d.path), "feeds-*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(append([]byte(xml.Header), data...)); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), d.path)
+}
+
+// sortFeeds orders feeds by title, ignoring case, and then by URL. Feeds
+// without a title sort by their URL.
+func sortFeeds(feeds []models.OPMLFeed) {
+	key := func(feed models.OPMLFeed) string {
+		if feed.Title == "" {
+			return strings.ToLower(feed.URL)
+		}
+		return strings.ToLower(feed.Title)
+	}
+	sort.Slice(feeds, func(i, j int) bool {
+		a, b := key(feeds[i]), key(feeds[j])
+		if a != b {
+			return a < b
+		}
+		return feeds[i].URL < feeds[j].URL
+	})
+}
@@ -0,0 +1,74 @@
+package services
+
+import (
+	"path/filepath"
+	"testing"
+
+	"rss-feed-to-csv/internal/models"
+)
+
+func feedURLs(feeds []models.OPMLFeed) []string {
+	urls := make([]string, len(feeds))
+	for i, feed := range feeds {
+		urls[i] = feed.URL
+	}
+	return urls
+}
+
+func TestFeedDirectory_Record(t *testing.T) {
+	directory, err := NewFeedDirectory("", 2)
+	if err != nil {
+		t.Fatalf("NewFeedDirectory() error = %v", err)
+	}
+	directory.Record(models.OPMLFeed{URL: "https://a.example/feed", Title: "A"})
+	directory.Record(models.OPMLFeed{URL: "https://b.example/feed", Title: "B"})
+	// Recording a again makes b the oldest feed
+	directory.Record(models.OPMLFeed{URL: "https://a.example/feed", Title: "A"})
+	directory.Record(models.OPMLFeed{URL: "https://c.example/feed", Title: "C"})
+
+	got := feedURLs(directory.Feeds(false))
+	want := []string{"https://a.example/feed", "https://c.example/feed"}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("Feeds() = %v, want %v", got, want)
+	}
+	if saved := directory.Feeds(true); len(saved) != 0 {
+		t.Errorf("Feeds(true) = %v, want none", feedURLs(saved))
+	}
+}
+
+func TestFeedDirectory_Save(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "feeds.opml")
+	directory, err := NewFeedDirectory(path, 10)
+	if err != nil {
+		t.Fatalf("NewFeedDirectory() error = %v", err)
+	}
+
+	directory.Record(models.OPMLFeed{URL: "https://b.example/feed", Title: "B"})
+	err = directory.Save([]models.OPMLFeed{
+		{URL: "https://b.example/feed", Categories: []string{"News"}},
+		{URL: "https://a.example/feed", Title: "A"},
+	})
+	if err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	// Exporting a saved feed fills in its missing title
+	directory.Record(models.OPMLFeed{URL: "https://b.example/feed", Title: "B", SiteURL: "https://b.example/"})
+
+	feeds := directory.Feeds(false)
+	if len(feeds) != 2 || feeds[0].URL != "https://a.example/feed" || feeds[1].Title != "B" || feeds[1].SiteURL != "https://b.example/" {
+		t.Errorf("Feeds() = %+v, want saved a and b with b's details", feeds)
+	}
+
+	// A second directory over the same file sees the saved feeds, as after a restart
+	reopened, err := NewFeedDirectory(path, 10)
+	if err != nil {
+		t.Fatalf("NewFeedDirectory() reopen error = %v", err)
+	}
+	feeds = reopened.Feeds(true)
+	if len(feeds) != 2 || feeds[0].Title != "A" || feeds[1].URL != "https://b.example/feed" {
+		t.Fatalf("reopened Feeds() = %+v, want a and b", feeds)
+	}
+	if len(feeds[1].Categories) != 1 || feeds[1].Categories[0] != "News" {
+		t.Errorf("reopened categories = %v, want [News]", feeds[1].Categories)
+	}
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"mime"
+	"strings"
+
+	"rss-feed-to-csv/internal/models"
+	"rss-feed-to-csv/internal/utils"
+)
+
+// FeedFormat identifies the dialect of a fetched feed document
+type FeedFormat string
+
+const (
+	FormatUnknown  FeedFormat = "unknown"
+	FormatRSS      FeedFormat = "rss"
+	FormatAtom     FeedFormat = "atom"
+	FormatRDF      FeedFormat = "rdf"
+	FormatJSONFeed FeedFormat = "jsonfeed"
+	// FormatHTML marks a web page rather than a feed, which may link to one
+	FormatHTML FeedFormat = "html"
+)
+
+// DetectFeedFormat determines the dialect of a feed document from its
+// Content-Type header, falling back to sniffing the body
+func DetectFeedFormat(body []byte, contentType string) FeedFormat {
+	if isJSONContentType(contentType) {
+		return FormatJSONFeed
+	}
+
+	if trimmed := bytes.TrimSpace(body); len(trimmed) > 0 && trimmed[0] == '{' {
+		return FormatJSONFeed
+	}
+
+	decoder := newXMLDecoder(body, false)
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			// Most HTML is not well-formed XML, so pages are also
+			// recognised by their opening markup
+			if looksLikeHTML(body) {
+				return FormatHTML
+			}
+			return FormatUnknown
+		}
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		switch {
+		case start.Name.Local == "rss":
+			return FormatRSS
+		case start.Name.Local == "feed" && start.Name.Space == models.AtomNamespace:
+			return FormatAtom
+		case start.Name.Local == "RDF" && start.Name.Space == models.RDFNamespace:
+			return FormatRDF
+		case strings.EqualFold(start.Name.Local, "html"):
+			return FormatHTML
+		default:
+			return FormatUnknown
+		}
+	}
+}
+
+// ParseOptions controls how feed documents are parsed
+type ParseOptions struct {
+	// Lenient repairs common XML errors instead of rejecting the feed
+	Lenient bool
+	// ResolveContentURLs also makes links and images inside item
+	// descriptions and content absolute when a feed is fetched
+	ResolveContentURLs bool
+}
+
+// ParseFeed parses a feed document in any supported dialect into the shared
+// RSS model. The body is transcoded to UTF-8 first, honouring the charset of
+// the Content-Type header and the XML declaration.
+func ParseFeed(body []byte, contentType string) (*models.RSS, error) {
+	rss, _, err := ParseFeedWithOptions(body, contentType, ParseOptions{})
+	return rss, err
+}
+
+// ParseFeedWithOptions parses a feed document like ParseFeed. In lenient
+// mode it also returns warnings describing what was repaired.
+func ParseFeedWithOptions(body []byte, contentType string, opts ParseOptions) (*models.RSS, []string, error) {
+	rss, warnings, err := parseFeed(toUTF8(body, contentType), contentType, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	parseDates(rss)
+	return rss, warnings, nil
+}
+
+// parseDates fills in the parsed form of every date in the feed
+func parseDates(rss *models.RSS) {
+	rss.Channel.LastBuildAt, _ = utils.ParseDate(rss.Channel.LastBuildDate)
+	for i := range rss.Channel.Items {
+		item := &rss.Channel.Items[i]
+		item.PublishedAt, _ = utils.ParseDate(item.PubDate)
+	}
+}
+
+// parseFeed decodes a UTF-8 feed document in whichever dialect it uses
+func parseFeed(body []byte, contentType string, opts ParseOptions) (*models.RSS, []string, error) {
+	format := DetectFeedFormat(body, contentType)
+	var warnings []string
+	if opts.Lenient && format != FormatJSONFeed {
+		body, warnings = scrubXML(body)
+		format = DetectFeedFormat(body, contentType)
+	}
+
+	switch format {
+	case FormatJSONFeed:
+		var feed models.JSONFeed
+		if err := json.Unmarshal(body, &feed); err != nil {
+			return nil, nil, fmt.Errorf("failed to parse JSON Feed: %w", err)
+		}
+		if !feed.IsValid() {
+			return nil, nil, errors.New("failed to parse JSON Feed: missing or unsupported version")
+		}
+		return feed.ToRSS(), nil, nil
+	case FormatHTML:
+		return nil, nil, errors.New("document is an HTML page, not a feed")
+	case FormatAtom:
+		feed, repairs, err := decodeFeedXML[models.AtomFeed](body, opts.Lenient)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse Atom XML: %w", err)
+		}
+		return feed.ToRSS(), append(warnings, repairs...), nil
+	case FormatRDF:
+		feed, repairs, err := decodeFeedXML[models.RDF](body, opts.Lenient)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse RDF XML: %w", err)
+		}
+		return feed.ToRSS(), append(warnings, repairs...), nil
+	default:
+		rss, repairs, err := decodeFeedXML[models.RSS](body, opts.Lenient)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse RSS XML: %w", err)
+		}
+		return rss, append(warnings, repairs...), nil
+	}
+}
+
+// looksLikeHTML reports whether a document opens with an HTML doctype or
+// root element, ignoring leading whitespace and comments
+func looksLikeHTML(body []byte) bool {
+	rest := bytes.TrimSpace(body)
+	for bytes.HasPrefix(rest, []byte("<!--")) {
+		end := bytes.Index(rest, []byte("-->"))
+		if end < 0 {
+			return false
+		}
+		rest = bytes.TrimSpace(rest[end+3:])
+	}
+	if len(rest) > 16 {
+		rest = rest[:16]
+	}
+	prefix := strings.ToLower(string(rest))
+	return strings.HasPrefix(prefix, "<!doctype html") || strings.HasPrefix(prefix, "<html")
+}
+
+// isJSONContentType reports whether a Content-Type header names a JSON media
+// type such as application/json or application/feed+json
+func isJSONContentType(contentType string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	return err == nil && strings.HasSuffix(mediaType, "json")
+}
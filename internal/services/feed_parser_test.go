@@ -0,0 +1,236 @@
+package services
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDetectFeedFormat(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		want        FeedFormat
+	}{
+		{
+			name: "RSS 2.0",
+			body: `<?xml version="1.0"?><rss version="2.0"><channel></channel></rss>`,
+			want: FormatRSS,
+		},
+		{
+			name: "Atom 1.0",
+			body: `<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"></feed>`,
+			want: FormatAtom,
+		},
+		{
+			name: "RSS 1.0 RDF",
+			body: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"></rdf:RDF>`,
+			want: FormatRDF,
+		},
+		{
+			name:        "JSON Feed by content type",
+			body:        ` {"version": "https://jsonfeed.org/version/1.1"}`,
+			contentType: "application/feed+json; charset=utf-8",
+			want:        FormatJSONFeed,
+		},
+		{
+			name: "JSON Feed by body sniffing",
+			body: `{"version": "https://jsonfeed.org/version/1.1", "items": []}`,
+			want: FormatJSONFeed,
+		},
+		{
+			name: "feed without Atom namespace",
+			body: `<feed></feed>`,
+			want: FormatUnknown,
+		},
+		{
+			name: "not XML",
+			body: `hello world`,
+			want: FormatUnknown,
+		},
+		{
+			name: "XHTML page",
+			body: `<?xml version="1.0"?><html xmlns="http://www.w3.org/1999/xhtml"><head></head></html>`,
+			want: FormatHTML,
+		},
+		{
+			name: "HTML page that is not well-formed XML",
+			body: "\n<!-- generated -->\n<!DOCTYPE html><html lang=en><head><meta charset=utf-8></head></html>",
+			want: FormatHTML,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DetectFeedFormat([]byte(tt.body), tt.contentType); got != tt.want {
+				t.Errorf("DetectFeedFormat() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseFeed(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		wantErr     bool
+		wantItems   int
+		wantTitle   string
+	}{
+		{
+			name: "RSS 2.0",
+			body: `<rss version="2.0"><channel>
+				<item><title>RSS Item</title><link>https://example.com/1</link></item>
+			</channel></rss>`,
+			wantItems: 1,
+			wantTitle: "RSS Item",
+		},
+		{
+			name: "Atom 1.0",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom">
+				<entry><title>Atom Item</title><link href="https://example.com/1"/></entry>
+				<entry><title>Atom Item 2</title><link href="https://example.com/2"/></entry>
+			</feed>`,
+			wantItems: 2,
+			wantTitle: "Atom Item",
+		},
+		{
+			name: "RSS 1.0 RDF",
+			body: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/">
+				<channel rdf:about="https://example.com/"><title>Channel</title></channel>
+				<item rdf:about="https://example.com/1"><title>RDF Item</title><link>https://example.com/1</link></item>
+			</rdf:RDF>`,
+			wantItems: 1,
+			wantTitle: "RDF Item",
+		},
+		{
+			name:        "JSON Feed",
+			body:        `{"version": "https://jsonfeed.org/version/1.1", "items": [{"id": "1", "title": "JSON Item"}]}`,
+			contentType: "application/feed+json",
+			wantItems:   1,
+			wantTitle:   "JSON Item",
+		},
+		{
+			name:    "JSON without feed version",
+			body:    `{"items": [{"id": "1"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "malformed XML",
+			body:    `<rss><channel><item>`,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rss, err := ParseFeed([]byte(tt.body), tt.contentType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFeed() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(rss.Channel.Items) != tt.wantItems {
+				t.Fatalf("len(Channel.Items) = %d, want %d", len(rss.Channel.Items), tt.wantItems)
+			}
+			if rss.Channel.Items[0].Title != tt.wantTitle {
+				t.Errorf("Items[0].Title = %q, want %q", rss.Channel.Items[0].Title, tt.wantTitle)
+			}
+		})
+	}
+}
+
+func TestParseFeedWithOptions_Lenient(t *testing.T) {
+	tests := []struct {
+		name         string
+		body         string
+		wantTitles   []string
+		wantDesc     string
+		wantWarnings []string
+	}{
+		{
+			name: "HTML entities and bare ampersands",
+			body: `<rss version="2.0"><channel><title>News & Views</title>
+				<item><title>Tom &amp; Jerry&nbsp;&mdash; Q&A</title><description>AT&T &copy; 2024 &bogus;</description></item>
+			</channel></rss>`,
+			wantTitles: []string{"Tom & Jerry — Q&A"},
+			wantDesc:   "AT&T © 2024 &bogus;",
+			wantWarnings: []string{
+				"decoded HTML entities &copy; &mdash; &nbsp;",
+				"kept unknown entities as text &bogus;",
+				"kept 3 unescaped ampersands as text",
+			},
+		},
+		{
+			name:         "control characters",
+			body:         "<rss version=\"2.0\"><channel><item><title>Bad\x0bTitle\x00</title></item></channel></rss>",
+			wantTitles:   []string{"BadTitle"},
+			wantWarnings: []string{"removed 2 invalid control characters"},
+		},
+		{
+			name:       "CDATA content is not reported",
+			body:       `<rss version="2.0"><channel><item><title>Plain</title><description><![CDATA[Fish & Chips &nbsp;]]></description></item></channel></rss>`,
+			wantTitles: []string{"Plain"},
+			wantDesc:   "Fish & Chips &nbsp;",
+		},
+		{
+			name: "truncated RSS keeps complete items",
+			body: `<?xml version="1.0"?><rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/"><channel><title>Feed</title>
+				<item><title>One</title><dc:creator>Ada</dc:creator></item>
+				<item><title>Two</title></item>
+				<item><title>Thr`,
+			wantTitles:   []string{"One", "Two"},
+			wantWarnings: []string{"salvaged 2 complete items"},
+		},
+		{
+			name: "truncated Atom keeps complete entries",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom"><title>Feed</title>
+				<entry><title>One</title></entry>
+				<entry><title>Two</title><summary>cut off`,
+			wantTitles:   []string{"One"},
+			wantWarnings: []string{"salvaged 1 complete items"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseFeed([]byte(tt.body), ""); err == nil && len(tt.wantWarnings) > 0 {
+				t.Error("ParseFeed() in strict mode should reject the feed")
+			}
+
+			rss, warnings, err := ParseFeedWithOptions([]byte(tt.body), "", ParseOptions{Lenient: true})
+			if err != nil {
+				t.Fatalf("ParseFeedWithOptions() error = %v", err)
+			}
+			if len(rss.Channel.Items) != len(tt.wantTitles) {
+				t.Fatalf("len(Items) = %d, want %d", len(rss.Channel.Items), len(tt.wantTitles))
+			}
+			for i, title := range tt.wantTitles {
+				if rss.Channel.Items[i].Title != title {
+					t.Errorf("Items[%d].Title = %q, want %q", i, rss.Channel.Items[i].Title, title)
+				}
+			}
+			if tt.wantDesc != "" && rss.Channel.Items[0].Description != tt.wantDesc {
+				t.Errorf("Description = %q, want %q", rss.Channel.Items[0].Description, tt.wantDesc)
+			}
+
+			if len(warnings) != len(tt.wantWarnings) {
+				t.Fatalf("warnings = %q, want %q", warnings, tt.wantWarnings)
+			}
+			for i, want := range tt.wantWarnings {
+				if !strings.Contains(warnings[i], want) {
+					t.Errorf("warnings[%d] = %q, want to contain %q", i, warnings[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestParseFeedWithOptions_LenientUnsalvageable(t *testing.T) {
+	_, _, err := ParseFeedWithOptions([]byte(`<rss><channel><item><title>Only`), "", ParseOptions{Lenient: true})
+	if err == nil {
+		t.Error("ParseFeedWithOptions() should fail when no complete item exists")
+	}
+}
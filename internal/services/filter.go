@@ -0,0 +1,437 @@
+package services
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+	"rss-feed-to-csv/internal/utils"
+)
+
+// Filter selects the items of a feed that match an expression such as
+//
+//	title contains "golang" AND (category in (news, tech) OR NOT has-image)
+//
+// A predicate compares a field, named like the columns parameter, with a
+// value using contains, equals, regex, before, after or in. The category
+// field tests each category of an item separately, and has-image tests
+// whether the item has an image. Predicates combine with AND, OR and NOT,
+// in that order of precedence, and parentheses.
+type Filter struct {
+	match filterFunc
+}
+
+// filterFunc reports whether an item matches a filter expression
+type filterFunc func(item *models.Item, channel *models.Channel) bool
+
+// ParseFilter parses a filter expression. Relative dates such as "7d" in
+// before and after predicates count back from now.
+func ParseFilter(expr string, now time.Time) (*Filter, error) {
+	tokens, err := tokenizeFilter(expr)
+	if err != nil {
+		return nil, err
+	}
+	if len(tokens) == 0 {
+		return nil, filterError("expression is empty")
+	}
+
+	p := &filterParser{tokens: tokens, now: now}
+	match, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if token, ok := p.peek(); ok {
+		return nil, filterError(fmt.Sprintf("unexpected %q at position %d", token.text, token.pos))
+	}
+	return &Filter{match: match}, nil
+}
+
+// Apply removes the items that do not match the filter from the feed
+func (f *Filter) Apply(rss *models.RSS) {
+	kept := rss.Channel.Items[:0]
+	for i := range rss.Channel.Items {
+		if f.match(&rss.Channel.Items[i], &rss.Channel) {
+			kept = append(kept, rss.Channel.Items[i])
+		}
+	}
+	rss.Channel.Items = kept
+}
+
+// filterToken is a word, quoted string or punctuation mark of a filter expression
+type filterToken struct {
+	text   string
+	quoted bool
+	// pos is the token's byte offset in the expression, counted from 1
+	pos int
+}
+
+// tokenizeFilter splits a filter expression into tokens. Strings may be
+// quoted with double or single quotes, and a backslash escapes the next
+// character inside quotes.
+func tokenizeFilter(expr string) ([]filterToken, error) {
+	var tokens []filterToken
+	for i := 0; i < len(expr); {
+		c, size := utf8.DecodeRuneInString(expr[i:])
+		switch {
+		case unicode.IsSpace(c):
+			i += size
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, filterToken{text: string(c), pos: i + 1})
+			i += size
+		case c == '"' || c == '\'':
+			start := i
+			var text strings.Builder
+			closed := false
+			for i += size; i < len(expr); {
+				r, n := utf8.DecodeRuneInString(expr[i:])
+				i += n
+				if r == c {
+					closed = true
+					break
+				}
+				if r == '\\' && i < len(expr) {
+					r, n = utf8.DecodeRuneInString(expr[i:])
+					i += n
+				}
+				text.WriteRune(r)
+			}
+			if !closed {
+				return nil, filterError(fmt.Sprintf("unterminated string at position %d", start+1))
+			}
+			tokens = append(tokens, filterToken{text: text.String(), quoted: true, pos: start + 1})
+		default:
+			start := i
+			for i < len(expr) {
+				r, n := utf8.DecodeRuneInString(expr[i:])
+				if unicode.IsSpace(r) || strings.ContainsRune(`(),"'`, r) {
+					break
+				}
+				i += n
+			}
+			tokens = append(tokens, filterToken{text: expr[start:i], pos: start + 1})
+		}
+	}
+	return tokens, nil
+}
+
+// filterParser is a recursive descent parser over the tokens of a filter expression
+type filterParser struct {
+	tokens []filterToken
+	next   int
+	now    time.Time
+}
+
+// peek returns the next token without consuming it
+func (p *filterParser) peek() (filterToken, bool) {
+	if p.next >= len(p.tokens) {
+		return filterToken{}, false
+	}
+	return p.tokens[p.next], true
+}
+
+// take consumes and returns the next token
+func (p *filterParser) take(expected string) (filterToken, error) {
+	token, ok := p.peek()
+	if !ok {
+		return token, filterError("expected " + expected + " at end of expression")
+	}
+	p.next++
+	return token, nil
+}
+
+// keyword consumes the next token if it is the given unquoted keyword
+func (p *filterParser) keyword(word string) bool {
+	token, ok := p.peek()
+	if ok && !token.quoted && strings.EqualFold(token.text, word) {
+		p.next++
+		return true
+	}
+	return false
+}
+
+// parseOr parses an expression: terms joined by OR
+func (p *filterParser) parseOr() (filterFunc, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		left = func(item *models.Item, channel *models.Channel) bool {
+			return a(item, channel) || b(item, channel)
+		}
+	}
+	return left, nil
+}
+
+// parseAnd parses a term: factors joined by AND
+func (p *filterParser) parseAnd() (filterFunc, error) {
+	left, err := p.parseNot()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		a, b := left, right
+		left = func(item *models.Item, channel *models.Channel) bool {
+			return a(item, channel) && b(item, channel)
+		}
+	}
+	return left, nil
+}
+
+// parseNot parses a factor: an optionally negated predicate or parenthesized expression
+func (p *filterParser) parseNot() (filterFunc, error) {
+	if p.keyword("not") {
+		inner, err := p.parseNot()
+		if err != nil {
+			return nil, err
+		}
+		return func(item *models.Item, channel *models.Channel) bool {
+			return !inner(item, channel)
+		}, nil
+	}
+
+	if p.keyword("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if !p.keyword(")") {
+			return nil, p.unexpected(`")"`)
+		}
+		return inner, nil
+	}
+	return p.parsePredicate()
+}
+
+// parsePredicate parses has-image or a field, operator and value
+func (p *filterParser) parsePredicate() (filterFunc, error) {
+	if p.keyword("has-image") {
+		return func(item *models.Item, _ *models.Channel) bool {
+			return item.GetImageURL() != ""
+		}, nil
+	}
+
+	field, err := p.take("a field")
+	if err != nil {
+		return nil, err
+	}
+	if field.quoted || strings.ContainsAny(field.text, "(),") || isFilterKeyword(field.text) {
+		return nil, filterError(fmt.Sprintf("expected a field at position %d, got %q", field.pos, field.text))
+	}
+	column, values, ok := filterField(field.text)
+	if !ok {
+		return nil, filterError(fmt.Sprintf("unknown field %q at position %d", field.text, field.pos))
+	}
+
+	op, err := p.take("an operator")
+	if err != nil {
+		return nil, err
+	}
+	switch strings.ToLower(op.text) {
+	case "contains":
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		value = strings.ToLower(value)
+		return anyValue(values, func(v string) bool {
+			return strings.Contains(strings.ToLower(v), value)
+		}), nil
+
+	case "equals":
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		return anyValue(values, func(v string) bool {
+			return strings.EqualFold(strings.TrimSpace(v), value)
+		}), nil
+
+	case "regex":
+		token, _ := p.peek()
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		pattern, err := regexp.Compile(value)
+		if err != nil {
+			return nil, filterError(fmt.Sprintf("invalid regex at position %d: %v", token.pos, err))
+		}
+		return anyValue(values, pattern.MatchString), nil
+
+	case "in":
+		list, err := p.list()
+		if err != nil {
+			return nil, err
+		}
+		return anyValue(values, func(v string) bool {
+			v = strings.TrimSpace(v)
+			for _, entry := range list {
+				if strings.EqualFold(v, entry) {
+					return true
+				}
+			}
+			return false
+		}), nil
+
+	case "before", "after":
+		if column.date == nil {
+			return nil, filterError(fmt.Sprintf("field %q is not a date and cannot be used with %s", field.text, op.text))
+		}
+		token, _ := p.peek()
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		bound, ok := p.parseDate(value)
+		if !ok {
+			return nil, filterError(fmt.Sprintf("invalid date %q at position %d: use a date such as 2024-01-31 or an age such as 7d", value, token.pos))
+		}
+		date, before := column.date, strings.EqualFold(op.text, "before")
+		return func(item *models.Item, channel *models.Channel) bool {
+			t := date(item, channel)
+			if t.IsZero() {
+				return false
+			}
+			if before {
+				return t.Before(bound)
+			}
+			return t.After(bound)
+		}, nil
+	}
+	return nil, filterError(fmt.Sprintf("unknown operator %q at position %d: must be contains, equals, regex, in, before or after", op.text, op.pos))
+}
+
+// value consumes a predicate's value, which is a quoted string or a word
+func (p *filterParser) value() (string, error) {
+	token, err := p.take("a value")
+	if err != nil {
+		return "", err
+	}
+	if !token.quoted && (token.text == "(" || token.text == ")" || token.text == ",") {
+		return "", filterError(fmt.Sprintf("expected a value at position %d, got %q", token.pos, token.text))
+	}
+	return token.text, nil
+}
+
+// list consumes a parenthesized, comma-separated list of values
+func (p *filterParser) list() ([]string, error) {
+	if !p.keyword("(") {
+		return nil, p.unexpected(`"(" starting a list`)
+	}
+	var values []string
+	for {
+		value, err := p.value()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+		if p.keyword(")") {
+			return values, nil
+		}
+		if !p.keyword(",") {
+			return nil, p.unexpected(`"," or ")"`)
+		}
+	}
+}
+
+// unexpected reports that the next token is not what the grammar expects
+func (p *filterParser) unexpected(expected string) error {
+	token, ok := p.peek()
+	if !ok {
+		return filterError("expected " + expected + " at end of expression")
+	}
+	return filterError(fmt.Sprintf("expected %s at position %d, got %q", expected, token.pos, token.text))
+}
+
+// parseDate reads the bound of a date predicate: an age such as 7d, 2w or
+// 36h counted back from now, or a date in any format feeds use
+func (p *filterParser) parseDate(value string) (time.Time, bool) {
+	if age, ok := parseAge(value); ok {
+		return p.now.Add(-age), true
+	}
+	return utils.ParseDate(value)
+}
+
+// parseAge parses an age given in days (7d), weeks (2w) or as a Go duration (36h)
+func parseAge(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	unit := map[byte]time.Duration{'d': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[value[len(value)-1]]
+	if unit != 0 {
+		n, err := strconv.Atoi(value[:len(value)-1])
+		if err != nil || n < 0 {
+			return 0, false
+		}
+		return time.Duration(n) * unit, true
+	}
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, false
+	}
+	return age, true
+}
+
+// filterField finds the column a predicate tests. The category field
+// yields every category of an item rather than their joined list.
+func filterField(name string) (Column, func(item *models.Item, channel *models.Channel) []string, bool) {
+	if strings.EqualFold(name, "category") {
+		return Column{Field: "category"}, func(item *models.Item, _ *models.Channel) []string {
+			values := make([]string, len(item.Categories))
+			for i, category := range item.Categories {
+				values[i] = category.Value
+			}
+			return values
+		}, true
+	}
+
+	column, ok := LookupColumn(name)
+	if !ok {
+		return Column{}, nil, false
+	}
+	return column, func(item *models.Item, channel *models.Channel) []string {
+		return []string{column.Value(item, channel, DefaultMultiValueSeparator)}
+	}, true
+}
+
+// anyValue builds a predicate that matches when any value of the field passes the test
+func anyValue(values func(item *models.Item, channel *models.Channel) []string, test func(string) bool) filterFunc {
+	return func(item *models.Item, channel *models.Channel) bool {
+		for _, value := range values(item, channel) {
+			if test(value) {
+				return true
+			}
+		}
+		return false
+	}
+}
+
+// isFilterKeyword reports whether a word is reserved by the filter grammar
+func isFilterKeyword(word string) bool {
+	switch strings.ToLower(word) {
+	case "and", "or", "not", "has-image":
+		return true
+	}
+	return false
+}
+
+// filterError reports an invalid filter expression
+func filterError(message string) error {
+	return &errors.ValidationError{Field: "filter", Message: message}
+}
@@ -0,0 +1,119 @@
+package services
+
+import (
+	stderrors "errors"
+	"testing"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+)
+
+func TestFilter_Apply(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	newFeed := func() *models.RSS {
+		return &models.RSS{Channel: models.Channel{Title: "Example", Items: []models.Item{
+			{
+				Title:       "Go 1.22 released",
+				Link:        "https://example.com/go",
+				PublishedAt: now.Add(-24 * time.Hour),
+				Categories:  []models.Category{{Value: "Tech"}, {Value: "Go"}},
+				Enclosures:  []models.Enclosure{{URL: "https://example.com/go.png", Type: "image/png"}},
+			},
+			{
+				Title:       "Weekly news roundup",
+				Link:        "https://example.com/news",
+				PublishedAt: now.Add(-10 * 24 * time.Hour),
+				Categories:  []models.Category{{Value: "News"}},
+			},
+			{
+				Title:      "Рим à la carte",
+				Link:       "https://example.net/rome",
+				Categories: []models.Category{{Value: "Travel"}},
+			},
+			{
+				Title:      "Undated post",
+				Link:       "https://example.org/post",
+				Categories: []models.Category{{Value: "tech"}},
+			},
+		}}}
+	}
+
+	tests := []struct {
+		name string
+		expr string
+		want []string
+	}{
+		{"contains ignores case", `title contains "GO"`, []string{"Go 1.22 released"}},
+		{"equals", `link equals https://example.com/news`, []string{"Weekly news roundup"}},
+		{"regex", `link regex '^https://example\.com/'`, []string{"Go 1.22 released", "Weekly news roundup"}},
+		{"category in list", `category in (news, TECH)`, []string{"Go 1.22 released", "Weekly news roundup", "Undated post"}},
+		{"category equals one of several", `category equals go`, []string{"Go 1.22 released"}},
+		{"after relative date", `pubDate after 7d`, []string{"Go 1.22 released"}},
+		{"before absolute date", `pubDate before 2024-03-05`, []string{"Weekly news roundup"}},
+		{"has image", `has-image`, []string{"Go 1.22 released"}},
+		{"not", `NOT has-image`, []string{"Weekly news roundup", "Рим à la carte", "Undated post"}},
+		{"and binds tighter than or", `title contains news or category equals tech and not has-image`, []string{"Weekly news roundup", "Undated post"}},
+		{"parentheses", `(title contains news or category equals tech) and has-image`, []string{"Go 1.22 released"}},
+		{"non-ASCII word", `title contains Рим`, []string{"Рим à la carte"}},
+		{"non-ASCII letter", `title contains à`, []string{"Рим à la carte"}},
+		{"non-ASCII quoted and escaped", `title equals "рим \À LA CARTE"`, []string{"Рим à la carte"}},
+		{"channel field", `feedTitle equals example and title contains undated`, []string{"Undated post"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			filter, err := ParseFilter(tt.expr, now)
+			if err != nil {
+				t.Fatalf("ParseFilter(%q) error = %v", tt.expr, err)
+			}
+			rss := newFeed()
+			filter.Apply(rss)
+
+			var got []string
+			for _, item := range rss.Channel.Items {
+				got = append(got, item.Title)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("Apply() kept %q, want %q", got, tt.want)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Apply() kept %q, want %q", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+}
+
+func TestParseFilter_Errors(t *testing.T) {
+	tests := []string{
+		"",
+		"title",
+		"title contains",
+		"bogus contains x",
+		"title resembles x",
+		`title contains "unterminated`,
+		"title regex (",
+		`title regex "["`,
+		"title before 7d",
+		"pubDate after yesterday-ish",
+		"category in news",
+		"category in (news tech)",
+		"(title contains x",
+		"title contains x y",
+		"title contains x and",
+		"not",
+	}
+
+	for _, expr := range tests {
+		t.Run(expr, func(t *testing.T) {
+			_, err := ParseFilter(expr, time.Now())
+			var validationErr *errors.ValidationError
+			if !stderrors.As(err, &validationErr) || validationErr.Field != "filter" {
+				t.Errorf("ParseFilter(%q) error = %v, want ValidationError on filter", expr, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"fmt"
+	"strings"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+// formulaTriggers are the leading characters that make spreadsheet
+// applications treat a cell as a formula
+const formulaTriggers = "=+-@\t\r"
+
+// FormulaProtection selects how CSV cells that would be interpreted as
+// spreadsheet formulas are neutralized
+type FormulaProtection string
+
+const (
+	// FormulaEscape prefixes the cell with an apostrophe so it is shown as text
+	FormulaEscape FormulaProtection = "escape"
+	// FormulaStrip removes the leading formula characters
+	FormulaStrip FormulaProtection = "strip"
+	// FormulaReject drops any row containing a formula cell
+	FormulaReject FormulaProtection = "reject"
+	// FormulaOff writes cells verbatim
+	FormulaOff FormulaProtection = "off"
+)
+
+// ParseFormulaProtection validates a formula protection mode. An empty name
+// selects FormulaEscape so protection is on by default.
+func ParseFormulaProtection(name string) (FormulaProtection, error) {
+	switch mode := FormulaProtection(strings.ToLower(name)); mode {
+	case "":
+		return FormulaEscape, nil
+	case FormulaEscape, FormulaStrip, FormulaReject, FormulaOff:
+		return mode, nil
+	default:
+		return "", &errors.ValidationError{
+			Field:   "formula_protection",
+			Message: fmt.Sprintf("unsupported mode %q: must be escape, strip, reject or off", name),
+		}
+	}
+}
+
+// isFormula reports whether a spreadsheet would evaluate the value as a formula
+func isFormula(value string) bool {
+	return value != "" && strings.ContainsRune(formulaTriggers, rune(value[0]))
+}
+
+// neutralize applies the protection mode to a cell value. It returns false
+// when the mode is FormulaReject and the value is a formula.
+func (p FormulaProtection) neutralize(value string) (string, bool) {
+	if p == FormulaOff || !isFormula(value) {
+		return value, true
+	}
+	switch p {
+	case FormulaStrip:
+		// Spaces are trimmed too so "- =cmd" cannot survive as " =cmd"
+		return strings.TrimLeft(value, formulaTriggers+" "), true
+	case FormulaReject:
+		return "", false
+	default:
+		return "'" + value, true
+	}
+}
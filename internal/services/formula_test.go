@@ -0,0 +1,61 @@
+package services
+
+import "testing"
+
+func TestParseFormulaProtection(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    FormulaProtection
+		wantErr bool
+	}{
+		{"", FormulaEscape, false},
+		{"escape", FormulaEscape, false},
+		{"STRIP", FormulaStrip, false},
+		{"reject", FormulaReject, false},
+		{"off", FormulaOff, false},
+		{"quote", "", true},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseFormulaProtection(tt.name)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseFormulaProtection(%q) error = %v, wantErr %v", tt.name, err, tt.wantErr)
+			continue
+		}
+		if got != tt.want {
+			t.Errorf("ParseFormulaProtection(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestFormulaProtection_Neutralize(t *testing.T) {
+	tests := []struct {
+		mode   FormulaProtection
+		value  string
+		want   string
+		wantOK bool
+	}{
+		{FormulaEscape, "=1+1", "'=1+1", true},
+		{FormulaEscape, "+1", "'+1", true},
+		{FormulaEscape, "-1", "'-1", true},
+		{FormulaEscape, "@SUM(A1)", "'@SUM(A1)", true},
+		{FormulaEscape, "\t=1", "'\t=1", true},
+		{FormulaEscape, "\r=1", "'\r=1", true},
+		{FormulaEscape, "Plain title", "Plain title", true},
+		{FormulaEscape, "a=b", "a=b", true},
+		{FormulaEscape, "", "", true},
+		{FormulaStrip, "=HYPERLINK(\"x\")", "HYPERLINK(\"x\")", true},
+		{FormulaStrip, "- =cmd", "cmd", true},
+		{FormulaStrip, "Plain title", "Plain title", true},
+		{FormulaReject, "@cmd", "", false},
+		{FormulaReject, "Plain title", "Plain title", true},
+		{FormulaOff, "=1+1", "=1+1", true},
+	}
+
+	for _, tt := range tests {
+		got, ok := tt.mode.neutralize(tt.value)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("%s.neutralize(%q) = %q, %v, want %q, %v", tt.mode, tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+
+	"rss-feed-to-csv/internal/models"
+	"rss-feed-to-csv/internal/utils"
+)
+
+// JSONExporter exports a feed as a single JSON document with the channel
+// metadata in a "feed" envelope and one object per item in "items"
+type JSONExporter struct {
+	sanitizer *utils.HTMLSanitizer
+}
+
+// NewJSONExporter creates a new JSON exporter
+func NewJSONExporter() *JSONExporter {
+	return &JSONExporter{
+		sanitizer: utils.NewHTMLSanitizer(),
+	}
+}
+
+// ContentType returns the media type of JSON output
+func (e *JSONExporter) ContentType() string {
+	return "application/json"
+}
+
+// FileExtension returns the file extension for JSON downloads
+func (e *JSONExporter) FileExtension() string {
+	return "json"
+}
+
+// Export writes the feed as JSON. Items are streamed one at a time, keyed by
+// column header in column order.
+func (e *JSONExporter) Export(ctx context.Context, w io.Writer, rss *models.RSS, opts ExportOptions) error {
+	bw := bufio.NewWriter(w)
+
+	bw.WriteString(`{"feed":`)
+	writeJSONObject(bw, e.sanitizer, opts, channelColumns, nil, &rss.Channel)
+
+	if opts.Mode != ModeChannel {
+		bw.WriteString(`,"items":[`)
+		columns := opts.columns()
+		for i, item := range opts.rows(rss) {
+			if i > 0 {
+				bw.WriteByte(',')
+			}
+			writeJSONObject(bw, e.sanitizer, opts, columns, item, &rss.Channel)
+		}
+		bw.WriteByte(']')
+	}
+
+	if len(opts.Summary) > 0 {
+		bw.WriteString(`,"summary":`)
+		writeJSONSummary(bw, opts.Summary)
+	}
+
+	bw.WriteString("}\n")
+	return bw.Flush()
+}
+
+// NDJSONExporter exports a feed as newline-delimited JSON, one item per line
+type NDJSONExporter struct {
+	sanitizer *utils.HTMLSanitizer
+}
+
+// NewNDJSONExporter creates a new NDJSON exporter
+func NewNDJSONExporter() *NDJSONExporter {
+	return &NDJSONExporter{
+		sanitizer: utils.NewHTMLSanitizer(),
+	}
+}
+
+// ContentType returns the media type of NDJSON output
+func (e *NDJSONExporter) ContentType() string {
+	return "application/x-ndjson"
+}
+
+// FileExtension returns the file extension for NDJSON downloads
+func (e *NDJSONExporter) FileExtension() string {
+	return "ndjson"
+}
+
+// Export writes one JSON object per item, flushing after every line so
+// consumers can process items as they arrive. A channel summary is a single
+// line, and a batch summary is appended as a final {"summary": [...]} line.
+func (e *NDJSONExporter) Export(ctx context.Context, w io.Writer, rss *models.RSS, opts ExportOptions) error {
+	bw := bufio.NewWriter(w)
+	columns := opts.columns()
+
+	for _, item := range opts.rows(rss) {
+		writeJSONObject(bw, e.sanitizer, opts, columns, item, &rss.Channel)
+		bw.WriteByte('\n')
+		if err := bw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(opts.Summary) > 0 {
+		bw.WriteString(`{"summary":`)
+		writeJSONSummary(bw, opts.Summary)
+		bw.WriteString("}\n")
+	}
+
+	return bw.Flush()
+}
+
+// writeJSONObject writes one row as a JSON object, preserving column order.
+// Write errors are sticky in bufio.Writer and surface on the next Flush.
+func writeJSONObject(bw *bufio.Writer, sanitizer *utils.HTMLSanitizer, opts ExportOptions, columns []Column, item *models.Item, channel *models.Channel) {
+	bw.WriteByte('{')
+	for i, column := range columns {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		bw.Write(jsonString(column.Header))
+		bw.WriteByte(':')
+		bw.Write(jsonString(opts.cellValue(sanitizer, column, item, channel)))
+	}
+	bw.WriteByte('}')
+}
+
+// writeJSONSummary writes the per-feed report of a batch export as an array
+// of objects keyed like the summary columns of the other formats
+func writeJSONSummary(bw *bufio.Writer, reports []FeedReport) {
+	bw.WriteByte('[')
+	for i, report := range reports {
+		if i > 0 {
+			bw.WriteByte(',')
+		}
+		record := report.record()
+		bw.WriteByte('{')
+		for j, header := range summaryHeaders {
+			if j > 0 {
+				bw.WriteByte(',')
+			}
+			bw.Write(jsonString(header))
+			bw.WriteByte(':')
+			// The item count is the one numeric value
+			if header == "Items" {
+				bw.WriteString(record[j])
+			} else {
+				bw.Write(jsonString(record[j]))
+			}
+		}
+		bw.WriteByte('}')
+	}
+	bw.WriteByte(']')
+}
+
+// jsonString encodes a string as JSON without escaping HTML characters
+func jsonString(value string) []byte {
+	var buf bytes.Buffer
+	encoder := json.NewEncoder(&buf)
+	encoder.SetEscapeHTML(false)
+	// Encoding a string cannot fail
+	_ = encoder.Encode(value)
+	return bytes.TrimSuffix(buf.Bytes(), []byte("\n"))
+}
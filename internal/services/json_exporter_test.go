@@ -0,0 +1,132 @@
+package services
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"rss-feed-to-csv/internal/models"
+)
+
+func testJSONFeed() *models.RSS {
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Title:    "Example Feed",
+			Language: "en",
+			Items: []models.Item{
+				{Title: "Item <1>", Link: "https://example.com/1", Description: "<p>One</p>"},
+				{Title: "Item 2", Link: "https://example.com/2", Categories: []models.Category{{Value: "a"}, {Value: "b"}}},
+			},
+		},
+	}
+	rss.Channel.SetLink("https://example.com/")
+	return rss
+}
+
+func TestJSONExporter_Export(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewJSONExporter().Export(context.Background(), &buf, testJSONFeed(), ExportOptions{SanitizeHTML: true})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var doc struct {
+		Feed  map[string]string   `json:"feed"`
+		Items []map[string]string `json:"items"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v\n%s", err, buf.String())
+	}
+
+	if doc.Feed["Title"] != "Example Feed" || doc.Feed["Link"] != "https://example.com/" || doc.Feed["ItemCount"] != "2" {
+		t.Errorf("feed envelope = %v", doc.Feed)
+	}
+	if len(doc.Items) != 2 {
+		t.Fatalf("len(items) = %d, want 2", len(doc.Items))
+	}
+	if doc.Items[0]["Title"] != "Item <1>" {
+		t.Errorf("items[0].Title = %q, want %q", doc.Items[0]["Title"], "Item <1>")
+	}
+	if doc.Items[0]["Description"] != "One" {
+		t.Errorf("items[0].Description = %q, want sanitized %q", doc.Items[0]["Description"], "One")
+	}
+	if doc.Items[1]["Categories"] != "a|b" {
+		t.Errorf("items[1].Categories = %q, want %q", doc.Items[1]["Categories"], "a|b")
+	}
+
+	// Keys keep column order and HTML characters are not escaped
+	if !strings.Contains(buf.String(), `{"Title":"Item <1>","Link":"https://example.com/1","Description":"One"`) {
+		t.Errorf("item object not in column order: %s", buf.String())
+	}
+}
+
+func TestJSONExporter_Export_ChannelMode(t *testing.T) {
+	var buf bytes.Buffer
+	err := NewJSONExporter().Export(context.Background(), &buf, testJSONFeed(), ExportOptions{Mode: ModeChannel})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(buf.Bytes(), &doc); err != nil {
+		t.Fatalf("Output is not valid JSON: %v", err)
+	}
+	if _, ok := doc["items"]; ok {
+		t.Error("channel mode should not include items")
+	}
+	if _, ok := doc["feed"]; !ok {
+		t.Error("channel mode should include the feed envelope")
+	}
+}
+
+func TestNDJSONExporter_Export(t *testing.T) {
+	columns, err := ParseColumns("title,link:url")
+	if err != nil {
+		t.Fatalf("ParseColumns() error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	err = NewNDJSONExporter().Export(context.Background(), &buf, testJSONFeed(), ExportOptions{Columns: columns, IncludeFeedColumns: true})
+	if err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	scanner := bufio.NewScanner(&buf)
+	var lines []map[string]string
+	for scanner.Scan() {
+		var obj map[string]string
+		if err := json.Unmarshal(scanner.Bytes(), &obj); err != nil {
+			t.Fatalf("line %q is not valid JSON: %v", scanner.Text(), err)
+		}
+		lines = append(lines, obj)
+	}
+
+	if len(lines) != 2 {
+		t.Fatalf("number of lines = %d, want 2", len(lines))
+	}
+	want := map[string]string{
+		"FeedTitle":    "Example Feed",
+		"FeedLink":     "https://example.com/",
+		"FeedLanguage": "en",
+		"Title":        "Item 2",
+		"url":          "https://example.com/2",
+	}
+	for key, value := range want {
+		if lines[1][key] != value {
+			t.Errorf("line 2 %s = %q, want %q", key, lines[1][key], value)
+		}
+	}
+	if len(lines[1]) != len(want) {
+		t.Errorf("line 2 has %d keys, want %d", len(lines[1]), len(want))
+	}
+}
+
+func TestNDJSONExporter_Export_WriterError(t *testing.T) {
+	err := NewNDJSONExporter().Export(context.Background(), &failingWriter{}, testJSONFeed(), ExportOptions{})
+	if err == nil {
+		t.Error("Export() should return error when writer fails")
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// maxListedEntities caps how many entity names a repair warning lists
+const maxListedEntities = 5
+
+// xmlPredefinedEntities are the entities every XML parser understands
+var xmlPredefinedEntities = map[string]bool{
+	"amp": true, "lt": true, "gt": true, "quot": true, "apos": true,
+}
+
+// newXMLDecoder creates a decoder for a UTF-8 document. In lenient mode it
+// accepts HTML entities, bare ampersands, unquoted attributes and unclosed
+// HTML void elements.
+func newXMLDecoder(body []byte, lenient bool) *xml.Decoder {
+	decoder := xml.NewDecoder(bytes.NewReader(body))
+	decoder.CharsetReader = utf8CharsetReader
+	if lenient {
+		decoder.Strict = false
+		decoder.Entity = xml.HTMLEntity
+		decoder.AutoClose = xml.HTMLAutoClose
+	}
+	return decoder
+}
+
+// decodeFeedXML unmarshals a feed document into a new T. In lenient mode a
+// document that still fails to parse is cut back to its last complete item
+// and parsed again, and a warning records the salvage.
+func decodeFeedXML[T any](body []byte, lenient bool) (*T, []string, error) {
+	feed := new(T)
+	err := newXMLDecoder(body, lenient).Decode(feed)
+	if err == nil || !lenient {
+		return feed, nil, err
+	}
+
+	repaired, items, ok := salvageItems(body)
+	if !ok {
+		return nil, nil, err
+	}
+	feed = new(T)
+	if err := newXMLDecoder(repaired, true).Decode(feed); err != nil {
+		return nil, nil, err
+	}
+	warning := fmt.Sprintf("feed is truncated or malformed (%v); salvaged %d complete items", err, items)
+	return feed, []string{warning}, nil
+}
+
+// scrubXML prepares a document for lenient parsing by removing characters
+// XML forbids, and describes the repairs lenient parsing will make
+func scrubXML(body []byte) ([]byte, []string) {
+	var warnings []string
+
+	scrubbed := make([]byte, 0, len(body))
+	removed := 0
+	for len(body) > 0 {
+		r, size := utf8.DecodeRune(body)
+		if isXMLChar(r) && !(r == utf8.RuneError && size == 1) {
+			scrubbed = append(scrubbed, body[:size]...)
+		} else {
+			removed++
+		}
+		body = body[size:]
+	}
+	if removed > 0 {
+		warnings = append(warnings, fmt.Sprintf("removed %d invalid control characters", removed))
+	}
+
+	return scrubbed, append(warnings, entityWarnings(scrubbed)...)
+}
+
+// isXMLChar reports whether r may appear in an XML 1.0 document
+func isXMLChar(r rune) bool {
+	return r == '\t' || r == '\n' || r == '\r' ||
+		(r >= 0x20 && r <= 0xD7FF) ||
+		(r >= 0xE000 && r <= 0xFFFD) ||
+		(r >= 0x10000 && r <= 0x10FFFF)
+}
+
+// entityWarnings reports HTML entities, unknown entities and bare ampersands
+// outside CDATA sections, which strict XML parsing would reject
+func entityWarnings(body []byte) []string {
+	htmlEntities := map[string]bool{}
+	unknownEntities := map[string]bool{}
+	bareAmpersands := 0
+
+	for _, text := range outsideCDATA(body) {
+		for {
+			i := bytes.IndexByte(text, '&')
+			if i < 0 {
+				break
+			}
+			text = text[i+1:]
+
+			end := bytes.IndexByte(text, ';')
+			if end <= 0 || !isEntityName(text[:end]) {
+				bareAmpersands++
+				continue
+			}
+			name := string(text[:end])
+			switch {
+			case name[0] == '#' || xmlPredefinedEntities[name]:
+			case xml.HTMLEntity[name] != "":
+				htmlEntities[name] = true
+			default:
+				unknownEntities[name] = true
+			}
+		}
+	}
+
+	var warnings []string
+	if len(htmlEntities) > 0 {
+		warnings = append(warnings, "decoded HTML entities "+listEntities(htmlEntities))
+	}
+	if len(unknownEntities) > 0 {
+		warnings = append(warnings, "kept unknown entities as text "+listEntities(unknownEntities))
+	}
+	if bareAmpersands > 0 {
+		warnings = append(warnings, fmt.Sprintf("kept %d unescaped ampersands as text", bareAmpersands))
+	}
+	return warnings
+}
+
+// isEntityName reports whether b is a plausible entity or character reference name
+func isEntityName(b []byte) bool {
+	if len(b) > 32 {
+		return false
+	}
+	for i, c := range b {
+		switch {
+		case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z':
+		case c >= '0' && c <= '9' && i > 0:
+		case c == '#' && i == 0:
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// listEntities formats entity names for a warning, listing at most a few
+func listEntities(names map[string]bool) string {
+	sorted := make([]string, 0, len(names))
+	for name := range names {
+		sorted = append(sorted, "&"+name+";")
+	}
+	sort.Strings(sorted)
+	if len(sorted) > maxListedEntities {
+		sorted = append(sorted[:maxListedEntities], "...")
+	}
+	return strings.Join(sorted, " ")
+}
+
+// outsideCDATA splits a document into the parts outside CDATA sections,
+// where entities are interpreted
+func outsideCDATA(body []byte) [][]byte {
+	var parts [][]byte
+	for {
+		start := bytes.Index(body, []byte("<![CDATA["))
+		if start < 0 {
+			return append(parts, body)
+		}
+		parts = append(parts, body[:start])
+		end := bytes.Index(body[start:], []byte("]]>"))
+		if end < 0 {
+			return parts
+		}
+		body = body[start+end+3:]
+	}
+}
+
+// salvageItems cuts a broken document back to the end of its last complete
+// item or entry and closes the elements still open at that point. It
+// returns false if there is nothing to salvage.
+func salvageItems(body []byte) ([]byte, int, bool) {
+	decoder := newXMLDecoder(body, true)
+
+	var stack, openAtCut []xml.Name
+	cut, items := int64(0), 0
+	for {
+		token, err := decoder.RawToken()
+		if err == io.EOF && len(stack) == 0 {
+			// The document is well formed, so the failure lies elsewhere
+			return nil, 0, false
+		}
+		if err != nil {
+			break
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			stack = append(stack, t.Name)
+		case xml.EndElement:
+			if len(stack) > 0 {
+				stack = stack[:len(stack)-1]
+			}
+			if t.Name.Local == "item" || t.Name.Local == "entry" {
+				cut = decoder.InputOffset()
+				openAtCut = append(openAtCut[:0], stack...)
+				items++
+			}
+		}
+	}
+	if items == 0 {
+		return nil, 0, false
+	}
+
+	repaired := append([]byte{}, body[:cut]...)
+	for i := len(openAtCut) - 1; i >= 0; i-- {
+		name := openAtCut[i].Local
+		if prefix := openAtCut[i].Space; prefix != "" {
+			name = prefix + ":" + name
+		}
+		repaired = append(repaired, "</"+name+">"...)
+	}
+	return repaired, items, true
+}
@@ -0,0 +1,147 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	stderrors "errors"
+	"io"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+// RetryPolicy controls how RSSFetcher retries transient failures
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first;
+	// one or less disables retries
+	MaxAttempts int
+	// BaseDelay is the backoff before the first retry, doubled for each retry after
+	BaseDelay time.Duration
+	// MaxDelay caps the backoff between attempts
+	MaxDelay time.Duration
+	// Deadline bounds the whole fetch including every retry and feed
+	// discovery; zero means no limit
+	Deadline time.Duration
+}
+
+// maxAttempts returns the attempt limit, never less than one
+func (p RetryPolicy) maxAttempts() int {
+	if p.MaxAttempts < 1 {
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+// backoff returns a randomized delay before the given retry (1 for the first
+// retry). Full jitter spreads retries from many clients across the window.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	if p.BaseDelay <= 0 {
+		return 0
+	}
+	ceiling := p.BaseDelay
+	for i := 1; i < retry && (p.MaxDelay <= 0 || ceiling < p.MaxDelay); i++ {
+		ceiling *= 2
+	}
+	if p.MaxDelay > 0 && ceiling > p.MaxDelay {
+		ceiling = p.MaxDelay
+	}
+	return rand.N(ceiling + 1)
+}
+
+// isRetryableStatus reports whether a response status is worth retrying
+func isRetryableStatus(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// isTransientError reports whether a request error is likely to succeed on
+// retry: 429 and 5xx responses, timeouts, temporary DNS failures, refused or
+// reset connections and connections closed mid-response. Everything else is
+// final, including certificate errors, redirect loops, blocked destinations,
+// oversized feeds and cancelled requests.
+func isTransientError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || stderrors.Is(err, errors.ErrBlockedAddress) {
+		return false
+	}
+
+	var fetchErr *errors.FetchError
+	if stderrors.As(err, &fetchErr) && fetchErr.StatusCode > 0 {
+		return isRetryableStatus(fetchErr.StatusCode)
+	}
+
+	var dnsErr *net.DNSError
+	if stderrors.As(err, &dnsErr) {
+		return dnsErr.IsTemporary || dnsErr.IsTimeout
+	}
+
+	if isTLSError(err) {
+		return false
+	}
+
+	var netErr net.Error
+	if stderrors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+	return stderrors.Is(err, syscall.ECONNREFUSED) ||
+		stderrors.Is(err, syscall.ECONNRESET) ||
+		stderrors.Is(err, io.EOF) ||
+		stderrors.Is(err, io.ErrUnexpectedEOF)
+}
+
+// isTLSError reports whether a request failed because the server's
+// certificate or TLS handshake was rejected, which retrying cannot fix
+func isTLSError(err error) bool {
+	var (
+		verifyErr    *tls.CertificateVerificationError
+		alertErr     tls.AlertError
+		recordErr    tls.RecordHeaderError
+		authorityErr x509.UnknownAuthorityError
+		invalidErr   x509.CertificateInvalidError
+		hostnameErr  x509.HostnameError
+	)
+	return stderrors.As(err, &verifyErr) ||
+		stderrors.As(err, &alertErr) ||
+		stderrors.As(err, &recordErr) ||
+		stderrors.As(err, &authorityErr) ||
+		stderrors.As(err, &invalidErr) ||
+		stderrors.As(err, &hostnameErr)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP date
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		if delay := date.Sub(now); delay > 0 {
+			return delay, true
+		}
+		return 0, true
+	}
+	return 0, false
+}
+
+// sleepContext waits for d or until ctx is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
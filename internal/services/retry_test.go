@@ -0,0 +1,92 @@
+package services
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	ceilings := []time.Duration{100 * time.Millisecond, 200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond, time.Second, time.Second}
+	for i, ceiling := range ceilings {
+		for range 50 {
+			if got := policy.backoff(i + 1); got < 0 || got > ceiling {
+				t.Fatalf("backoff(%d) = %v, want within [0, %v]", i+1, got, ceiling)
+			}
+		}
+	}
+
+	if got := (RetryPolicy{}).backoff(3); got != 0 {
+		t.Errorf("backoff() without base delay = %v, want 0", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		value  string
+		want   time.Duration
+		wantOK bool
+	}{
+		{"", 0, false},
+		{"120", 2 * time.Minute, true},
+		{"-1", 0, false},
+		{"Mon, 01 Jan 2024 12:00:30 GMT", 30 * time.Second, true},
+		{"Mon, 01 Jan 2024 11:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+
+	for _, tt := range tests {
+		got, ok := parseRetryAfter(tt.value, now)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("parseRetryAfter(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestIsTransientError(t *testing.T) {
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		err  error
+		want bool
+	}{
+		{"503", context.Background(), &errors.FetchError{StatusCode: 503}, true},
+		{"429", context.Background(), &errors.FetchError{StatusCode: 429}, true},
+		{"404", context.Background(), &errors.FetchError{StatusCode: 404}, false},
+		{"connection refused", context.Background(), &errors.FetchError{Err: &net.OpError{Op: "dial", Err: os.NewSyscallError("connect", syscall.ECONNREFUSED)}}, true},
+		{"connection reset", context.Background(), &errors.FetchError{Err: &net.OpError{Op: "read", Err: os.NewSyscallError("read", syscall.ECONNRESET)}}, true},
+		{"timeout", context.Background(), &errors.FetchError{Err: &url.Error{Op: "Get", Err: context.DeadlineExceeded}}, true},
+		{"unknown certificate authority", context.Background(), &errors.FetchError{Err: &url.Error{Op: "Get", Err: &tls.CertificateVerificationError{Err: x509.UnknownAuthorityError{}}}}, false},
+		{"certificate for another host", context.Background(), &errors.FetchError{Err: &url.Error{Op: "Get", Err: x509.HostnameError{Host: "example.com", Certificate: &x509.Certificate{}}}}, false},
+		{"redirect loop", context.Background(), &errors.FetchError{Err: &url.Error{Op: "Get", Err: fmt.Errorf("stopped after 10 redirects")}}, false},
+		{"unexpected EOF", context.Background(), &errors.FetchError{Err: io.ErrUnexpectedEOF}, true},
+		{"temporary DNS failure", context.Background(), &net.DNSError{IsTemporary: true}, true},
+		{"unknown host", context.Background(), &net.DNSError{IsNotFound: true}, false},
+		{"blocked address", context.Background(), &errors.FetchError{Err: &net.OpError{Op: "dial", Err: errors.ErrBlockedAddress}}, false},
+		{"feed too large", context.Background(), &errors.FeedTooLargeError{}, false},
+		{"cancelled request", cancelled, &errors.FetchError{Err: &net.OpError{Op: "dial", Err: context.Canceled}}, false},
+	}
+
+	for _, tt := range tests {
+		if got := isTransientError(tt.ctx, tt.err); got != tt.want {
+			t.Errorf("isTransientError(%s) = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}
@@ -2,72 +2,364 @@ package services
 
 import (
 	"context"
-	"encoding/xml"
+	stderrors "errors"
 	"fmt"
 	"io"
+	"log"
+	"net"
 	"net/http"
+	neturl "net/url"
+	"strings"
 	"time"
 
 	"rss-feed-to-csv/internal/errors"
 	"rss-feed-to-csv/internal/models"
+	"rss-feed-to-csv/internal/validator"
 )
 
 // RSSFetcher handles fetching and parsing RSS feeds
 type RSSFetcher struct {
 	client    *http.Client
 	userAgent string
+	maxSize   int64
+	cache     FeedCache
+	cacheTTL  time.Duration
+	retry     RetryPolicy
+	breaker   *CircuitBreaker
+	now       func() time.Time
+	sleep     func(ctx context.Context, d time.Duration) error
+}
+
+// FetcherOptions configures an RSSFetcher
+type FetcherOptions struct {
+	// Timeout bounds each HTTP request
+	Timeout time.Duration
+	// UserAgent is sent with every request
+	UserAgent string
+	// MaxSize rejects feeds larger than this many bytes; zero or less disables the limit
+	MaxSize int64
+	// AddressPolicy, when set, checks every connection including those made for redirects
+	AddressPolicy *validator.AddressPolicy
+	// Cache, when set, stores fetched feeds for conditional GETs
+	Cache FeedCache
+	// CacheTTL is how long cached feeds are served without revalidation
+	// when the response has no Cache-Control max-age
+	CacheTTL time.Duration
+	// Retry controls retries of transient network errors and 5xx/429 responses
+	Retry RetryPolicy
+	// Breaker, when set, fails fast for hosts with repeated failures
+	Breaker *CircuitBreaker
 }
 
 // NewRSSFetcher creates a new RSS fetcher with configured HTTP client
-func NewRSSFetcher(timeout time.Duration, userAgent string) *RSSFetcher {
+func NewRSSFetcher(opts FetcherOptions) *RSSFetcher {
+	client := &http.Client{
+		Timeout: opts.Timeout,
+	}
+	if opts.AddressPolicy != nil {
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		// Proxies are bypassed, otherwise the dial-time checks would only
+		// ever see the proxy's address
+		transport.Proxy = nil
+		transport.DialContext = opts.AddressPolicy.DialContext(&net.Dialer{
+			Timeout:   30 * time.Second,
+			KeepAlive: 30 * time.Second,
+		})
+		client.Transport = transport
+		client.CheckRedirect = opts.AddressPolicy.CheckRedirect
+	}
+
 	return &RSSFetcher{
-		client: &http.Client{
-			Timeout: timeout,
-		},
-		userAgent: userAgent,
+		client:    client,
+		userAgent: opts.UserAgent,
+		maxSize:   opts.MaxSize,
+		cache:     opts.Cache,
+		cacheTTL:  opts.CacheTTL,
+		retry:     opts.Retry,
+		breaker:   opts.Breaker,
+		now:       time.Now,
+		sleep:     sleepContext,
 	}
 }
 
-// FetchRSS fetches and parses an RSS feed from the given URL
+// FetchResult is a fetched and parsed feed
+type FetchResult struct {
+	RSS *models.RSS
+	// Warnings describes repairs made while parsing in lenient mode
+	Warnings []string
+	// FeedURL is the feed that was parsed, which differs from the requested
+	// URL when it was discovered from an HTML page
+	FeedURL string
+}
+
+// FetchRSS fetches and parses an RSS, Atom, RDF or JSON feed from the given URL
 func (f *RSSFetcher) FetchRSS(ctx context.Context, url string) (*models.RSS, error) {
+	result, err := f.FetchFeed(ctx, url, ParseOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return result.RSS, nil
+}
+
+// FetchFeed fetches and parses a feed like FetchRSS, with control over
+// parsing. When the URL serves an HTML page instead, the feeds it links to
+// and the site's common feed paths are tried in turn. The retry deadline
+// covers the whole call, discovery included.
+func (f *RSSFetcher) FetchFeed(ctx context.Context, url string, opts ParseOptions) (*FetchResult, error) {
+	if f.retry.Deadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, f.retry.Deadline)
+		defer cancel()
+	}
+
+	feed, err := f.fetchFeed(ctx, url, f.retry.maxAttempts())
+	if err != nil {
+		return nil, err
+	}
+
+	if isHTMLPage(feed) {
+		return f.discoverFeed(ctx, url, feed.Body, opts)
+	}
+	return parseFetchedFeed(url, feed, opts)
+}
+
+// discoverFeed follows the feed candidates of an HTML page and returns the
+// first one that parses as a feed with items. Candidates get a single
+// attempt each: most are guesses, and a missing one should not use up the
+// retry budget of the request.
+func (f *RSSFetcher) discoverFeed(ctx context.Context, pageURL string, page []byte, opts ParseOptions) (*FetchResult, error) {
+	for _, candidate := range discoverFeedURLs(page, pageURL) {
+		feed, err := f.fetchFeed(ctx, candidate, 1)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, err
+			}
+			continue
+		}
+		if isHTMLPage(feed) {
+			continue
+		}
+		if result, err := parseFetchedFeed(candidate, feed, opts); err == nil {
+			log.Printf("[INFO] Discovered feed - Page: %s, Feed: %s", pageURL, candidate)
+			return result, nil
+		}
+	}
+	return nil, fmt.Errorf("%w: %s", errors.ErrNoFeedFound, pageURL)
+}
+
+// parseFetchedFeed parses a fetched feed, checks that it has items,
+// resolves its relative URLs against the URL it was fetched from and
+// records that URL on every item
+func parseFetchedFeed(url string, feed CachedFeed, opts ParseOptions) (*FetchResult, error) {
+	rss, warnings, err := ParseFeedWithOptions(feed.Body, feed.ContentType, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// Validate RSS has content
+	if len(rss.Channel.Items) == 0 {
+		return nil, errors.ErrNoRSSItems
+	}
+
+	resolveURLs(rss, url, opts.ResolveContentURLs)
+	for i := range rss.Channel.Items {
+		rss.Channel.Items[i].FeedURL = url
+		rss.Channel.Items[i].FeedTitle = rss.Channel.Title
+	}
+	return &FetchResult{RSS: rss, Warnings: warnings, FeedURL: url}, nil
+}
+
+// isHTMLPage reports whether a fetched document is a web page rather than a feed
+func isHTMLPage(feed CachedFeed) bool {
+	return DetectFeedFormat(toUTF8(feed.Body, feed.ContentType), feed.ContentType) == FormatHTML
+}
+
+// fetchFeed returns the raw feed, serving it from the cache while fresh and
+// otherwise revalidating any cached copy with a conditional GET. Transient
+// failures are retried with the policy's backoff, up to maxAttempts in total.
+func (f *RSSFetcher) fetchFeed(ctx context.Context, url string, maxAttempts int) (CachedFeed, error) {
+	var cached CachedFeed
+	hasCached := false
+	if f.cache != nil {
+		cached, hasCached = f.cache.Get(url)
+		if hasCached && f.now().Before(cached.ExpiresAt) {
+			return cached, nil
+		}
+	}
+
+	// The breaker sees the whole fetch as one request, so the retries of a
+	// single fetch cannot open it on their own
+	host := requestHost(url)
+	if err := f.breaker.Allow(host); err != nil {
+		return CachedFeed{}, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		feed, retryAfter, err := f.request(ctx, url, cached, hasCached)
+		if err == nil {
+			f.breaker.Success(host)
+			return feed, nil
+		}
+
+		if attempt < maxAttempts && isTransientError(ctx, err) {
+			delay := f.retry.backoff(attempt)
+			if retryAfter > delay {
+				delay = retryAfter
+			}
+			// Only wait if the next attempt can still start before the deadline
+			deadline, hasDeadline := ctx.Deadline()
+			if !hasDeadline || time.Until(deadline) > delay {
+				if f.sleep(ctx, delay) == nil {
+					continue
+				}
+			}
+		}
+
+		f.recordOutcome(ctx, host, err)
+		var fetchErr *errors.FetchError
+		if stderrors.As(err, &fetchErr) {
+			fetchErr.Attempts = attempt
+		}
+		return CachedFeed{}, err
+	}
+}
+
+// recordOutcome reports a failed fetch to the circuit breaker. Only errors
+// that point at an unhealthy host count as failures; a host that answers,
+// even with a client error, is up.
+func (f *RSSFetcher) recordOutcome(ctx context.Context, host string, err error) {
+	var fetchErr *errors.FetchError
+	switch {
+	case ctx.Err() != nil:
+		// A cancelled fetch says nothing about the host's health
+		f.breaker.Cancel(host)
+	case isTransientError(ctx, err):
+		f.breaker.Failure(host)
+	case stderrors.As(err, &fetchErr) && fetchErr.StatusCode > 0:
+		f.breaker.Success(host)
+	default:
+		f.breaker.Cancel(host)
+	}
+}
+
+// requestHost returns the host and port a feed URL points at, used to key
+// the circuit breaker. The port is part of the key so that failures on a
+// port nobody serves feeds from cannot open the breaker of the real site.
+func requestHost(rawURL string) string {
+	parsed, err := neturl.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	port := parsed.Port()
+	if port == "" {
+		port = "80"
+		if strings.EqualFold(parsed.Scheme, "https") {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(parsed.Hostname(), port)
+}
+
+// request performs a single GET. For retryable responses it also returns
+// the delay requested by the server's Retry-After header.
+func (f *RSSFetcher) request(ctx context.Context, url string, cached CachedFeed, hasCached bool) (CachedFeed, time.Duration, error) {
 	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return CachedFeed{}, 0, fmt.Errorf("failed to create request: %w", err)
 	}
-	
+
 	// Set headers to improve compatibility with RSS servers
 	req.Header.Set("User-Agent", f.userAgent)
-	req.Header.Set("Accept", "application/rss+xml, application/xml, text/xml, */*")
-	
+	req.Header.Set("Accept", "application/rss+xml, application/atom+xml, application/rdf+xml, application/feed+json, application/xml, text/xml, */*")
+	if hasCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
 	resp, err := f.client.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to fetch RSS feed: %w", err)
+		return CachedFeed{}, 0, &errors.FetchError{URL: url, Err: err}
 	}
 	defer resp.Body.Close()
 
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			cached.ETag = etag
+		}
+		if lastModified := resp.Header.Get("Last-Modified"); lastModified != "" {
+			cached.LastModified = lastModified
+		}
+		f.store(url, cached, resp.Header)
+		return cached, 0, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, &errors.FetchError{
+		retryAfter, _ := parseRetryAfter(resp.Header.Get("Retry-After"), f.now())
+		return CachedFeed{}, retryAfter, &errors.FetchError{
 			URL:        url,
 			StatusCode: resp.StatusCode,
 			Err:        fmt.Errorf("unexpected status: %s", resp.Status),
 		}
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	body, err := f.readBody(url, resp)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read RSS feed: %w", err)
+		return CachedFeed{}, 0, err
 	}
 
-	var rss models.RSS
-	err = xml.Unmarshal(body, &rss)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse RSS XML: %w", err)
+	feed := CachedFeed{
+		Body:         body,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		FetchedAt:    f.now(),
 	}
+	f.store(url, feed, resp.Header)
+	return feed, 0, nil
+}
 
-	// Validate RSS has content
-	if len(rss.Channel.Items) == 0 {
-		return nil, errors.ErrNoRSSItems
+// store saves a feed in the cache with an expiry derived from the response
+// headers. Caching is best effort, so write failures are only logged.
+func (f *RSSFetcher) store(url string, feed CachedFeed, header http.Header) {
+	if f.cache == nil {
+		return
+	}
+	expiresAt, ok := cacheExpiry(header, f.now(), f.cacheTTL)
+	if !ok {
+		return
+	}
+	feed.ExpiresAt = expiresAt
+	if err := f.cache.Set(url, feed); err != nil {
+		log.Printf("[WARN] Failed to cache feed - URL: %s, Error: %v", url, err)
+	}
+}
+
+// readBody reads the response body, enforcing the size limit. A declared
+// Content-Length over the limit fails before anything is read; otherwise the
+// read stops one byte past the limit so oversized bodies are never buffered.
+func (f *RSSFetcher) readBody(url string, resp *http.Response) ([]byte, error) {
+	if f.maxSize <= 0 {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return nil, &errors.FetchError{URL: url, Err: fmt.Errorf("failed to read RSS feed: %w", err)}
+		}
+		return body, nil
+	}
+
+	if resp.ContentLength > f.maxSize {
+		return nil, &errors.FeedTooLargeError{URL: url, Limit: f.maxSize, Size: resp.ContentLength}
 	}
 
-	return &rss, nil
-}
\ No newline at end of file
+	body, err := io.ReadAll(io.LimitReader(resp.Body, f.maxSize+1))
+	if err != nil {
+		return nil, &errors.FetchError{URL: url, Err: fmt.Errorf("failed to read RSS feed: %w", err)}
+	}
+	if int64(len(body)) > f.maxSize {
+		return nil, &errors.FeedTooLargeError{URL: url, Limit: f.maxSize}
+	}
+	return body, nil
+}
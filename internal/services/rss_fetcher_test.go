@@ -0,0 +1,596 @@
+package services
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"log"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/validator"
+)
+
+const testFeed = `<?xml version="1.0"?><rss version="2.0"><channel><title>Test</title><item><title>Item 1</title></item></channel></rss>`
+
+func TestRSSFetcher_FetchRSS_MaxSize(t *testing.T) {
+	padding := strings.Repeat(" ", 2048)
+
+	tests := []struct {
+		name     string
+		body     string
+		chunked  bool
+		maxSize  int64
+		wantErr  bool
+		wantSize int64
+	}{
+		{name: "within limit", body: testFeed, maxSize: 1024},
+		{name: "limit disabled", body: testFeed + padding, maxSize: 0},
+		{name: "declared length over limit", body: testFeed + padding, maxSize: 1024, wantErr: true, wantSize: int64(len(testFeed + padding))},
+		{name: "streamed body over limit", body: testFeed + padding, chunked: true, maxSize: 1024, wantErr: true},
+		{name: "exactly at limit", body: testFeed, maxSize: int64(len(testFeed))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "application/rss+xml")
+				if tt.chunked {
+					// Flushing before the body is written forces chunked encoding
+					w.(http.Flusher).Flush()
+				} else {
+					w.Header().Set("Content-Length", strconv.Itoa(len(tt.body)))
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer server.Close()
+
+			fetcher := NewRSSFetcher(FetcherOptions{Timeout: 5 * time.Second, UserAgent: "test-agent", MaxSize: tt.maxSize})
+			rss, err := fetcher.FetchRSS(context.Background(), server.URL)
+
+			if !tt.wantErr {
+				if err != nil {
+					t.Fatalf("FetchRSS() error = %v", err)
+				}
+				if len(rss.Channel.Items) != 1 {
+					t.Errorf("len(Items) = %d, want 1", len(rss.Channel.Items))
+				}
+				return
+			}
+
+			var tooLarge *errors.FeedTooLargeError
+			if !stderrors.As(err, &tooLarge) {
+				t.Fatalf("FetchRSS() error = %v, want FeedTooLargeError", err)
+			}
+			if tooLarge.Limit != tt.maxSize || tooLarge.Size != tt.wantSize {
+				t.Errorf("FeedTooLargeError = %+v, want Limit %d, Size %d", tooLarge, tt.maxSize, tt.wantSize)
+			}
+		})
+	}
+}
+
+func TestRSSFetcher_FetchRSS_AddressPolicy(t *testing.T) {
+	feed := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testFeed))
+	}))
+	defer feed.Close()
+
+	redirect := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data/", http.StatusFound)
+	}))
+	defer redirect.Close()
+
+	blockAll, err := validator.NewAddressPolicy(nil, nil, nil)
+	if err != nil {
+		t.Fatalf("NewAddressPolicy() error = %v", err)
+	}
+	allowLoopback, err := validator.NewAddressPolicy(nil, []string{"127.0.0.0/8", "::1"}, nil)
+	if err != nil {
+		t.Fatalf("NewAddressPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		url         string
+		policy      *validator.AddressPolicy
+		wantBlocked bool
+	}{
+		{name: "loopback feed blocked", url: feed.URL, policy: blockAll, wantBlocked: true},
+		{name: "loopback feed allowlisted", url: feed.URL, policy: allowLoopback},
+		{name: "redirect to metadata endpoint blocked", url: redirect.URL, policy: allowLoopback, wantBlocked: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fetcher := NewRSSFetcher(FetcherOptions{Timeout: 5 * time.Second, UserAgent: "test-agent", AddressPolicy: tt.policy})
+			_, err := fetcher.FetchRSS(context.Background(), tt.url)
+			if blocked := stderrors.Is(err, errors.ErrBlockedAddress); blocked != tt.wantBlocked {
+				t.Errorf("FetchRSS() error = %v, want blocked %v", err, tt.wantBlocked)
+			}
+		})
+	}
+}
+
+func TestRSSFetcher_FetchRSS_Cache(t *testing.T) {
+	var requests, notModified int
+	var cacheControl string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if r.Header.Get("If-None-Match") == `"v1"` && r.Header.Get("If-Modified-Since") == "Mon, 01 Jan 2024 00:00:00 GMT" {
+			notModified++
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", "Mon, 01 Jan 2024 00:00:00 GMT")
+		if cacheControl != "" {
+			w.Header().Set("Cache-Control", cacheControl)
+		}
+		w.Write([]byte(testFeed))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	fetcher := NewRSSFetcher(FetcherOptions{
+		Timeout:  5 * time.Second,
+		Cache:    NewMemoryFeedCache(CacheLimits{MaxEntries: 10}),
+		CacheTTL: time.Minute,
+	})
+	fetcher.now = func() time.Time { return now }
+
+	fetch := func() {
+		t.Helper()
+		rss, err := fetcher.FetchRSS(context.Background(), server.URL)
+		if err != nil {
+			t.Fatalf("FetchRSS() error = %v", err)
+		}
+		if len(rss.Channel.Items) != 1 {
+			t.Fatalf("len(Items) = %d, want 1", len(rss.Channel.Items))
+		}
+	}
+
+	fetch()
+	fetch()
+	if requests != 1 {
+		t.Errorf("requests = %d, want fresh entry served from cache", requests)
+	}
+
+	// Once the TTL passes the entry is revalidated and the 304 is served from cache
+	now = now.Add(2 * time.Minute)
+	fetch()
+	if requests != 2 || notModified != 1 {
+		t.Errorf("requests = %d, 304s = %d, want one conditional request", requests, notModified)
+	}
+	fetch()
+	if requests != 2 {
+		t.Errorf("requests = %d, want revalidated entry to be fresh again", requests)
+	}
+
+	// max-age overrides the TTL; no-store keeps the response out of the cache
+	fetcher.cache = NewMemoryFeedCache(CacheLimits{MaxEntries: 10})
+	cacheControl = "max-age=600"
+	fetch()
+	now = now.Add(5 * time.Minute)
+	fetch()
+	if requests != 3 {
+		t.Errorf("requests = %d, want max-age to keep the entry fresh", requests)
+	}
+
+	fetcher.cache = NewMemoryFeedCache(CacheLimits{MaxEntries: 10})
+	cacheControl = "no-store"
+	fetch()
+	fetch()
+	if requests != 5 || notModified != 1 {
+		t.Errorf("requests = %d, 304s = %d, want no-store responses refetched in full", requests, notModified)
+	}
+}
+
+func TestRSSFetcher_FetchRSS_Retry(t *testing.T) {
+	tests := []struct {
+		name         string
+		responses    []int
+		retryAfter   string
+		policy       RetryPolicy
+		wantErr      bool
+		wantRequests int
+		wantDelays   []time.Duration
+	}{
+		{
+			name:         "recovers from 502 and 503",
+			responses:    []int{502, 503, 200},
+			policy:       RetryPolicy{MaxAttempts: 3},
+			wantRequests: 3,
+			wantDelays:   []time.Duration{0, 0},
+		},
+		{
+			name:         "honours Retry-After on 429",
+			responses:    []int{429, 200},
+			retryAfter:   "7",
+			policy:       RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: time.Millisecond},
+			wantRequests: 2,
+			wantDelays:   []time.Duration{7 * time.Second},
+		},
+		{
+			name:         "gives up after max attempts",
+			responses:    []int{503, 503, 503, 200},
+			policy:       RetryPolicy{MaxAttempts: 3},
+			wantErr:      true,
+			wantRequests: 3,
+			wantDelays:   []time.Duration{0, 0},
+		},
+		{
+			name:         "does not retry client errors",
+			responses:    []int{404, 200},
+			policy:       RetryPolicy{MaxAttempts: 3},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+		{
+			name:         "stops when Retry-After exceeds the deadline",
+			responses:    []int{503, 200},
+			retryAfter:   "60",
+			policy:       RetryPolicy{MaxAttempts: 3, Deadline: 5 * time.Second},
+			wantErr:      true,
+			wantRequests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.responses[requests]
+				requests++
+				if status != http.StatusOK {
+					w.Header().Set("Retry-After", tt.retryAfter)
+					w.WriteHeader(status)
+					return
+				}
+				w.Write([]byte(testFeed))
+			}))
+			defer server.Close()
+
+			fetcher := NewRSSFetcher(FetcherOptions{Timeout: 5 * time.Second, Retry: tt.policy})
+			var delays []time.Duration
+			fetcher.sleep = func(_ context.Context, d time.Duration) error {
+				delays = append(delays, d)
+				return nil
+			}
+
+			_, err := fetcher.FetchRSS(context.Background(), server.URL)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("FetchRSS() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if fmt.Sprint(delays) != fmt.Sprint(tt.wantDelays) {
+				t.Errorf("delays = %v, want %v", delays, tt.wantDelays)
+			}
+			if tt.wantErr {
+				var fetchErr *errors.FetchError
+				if !stderrors.As(err, &fetchErr) || fetchErr.Attempts != tt.wantRequests {
+					t.Errorf("FetchRSS() error = %v, want FetchError with %d attempts", err, tt.wantRequests)
+				}
+			}
+		})
+	}
+}
+
+func TestRSSFetcher_FetchRSS_FinalErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		tls     bool
+		handler func(w http.ResponseWriter, r *http.Request)
+		// wantRequests is the number of requests one attempt makes
+		wantRequests int
+	}{
+		{
+			name:         "untrusted certificate",
+			tls:          true,
+			handler:      func(w http.ResponseWriter, r *http.Request) { w.Write([]byte(testFeed)) },
+			wantRequests: 0,
+		},
+		{
+			name: "redirect loop",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Redirect(w, r, r.URL.Path, http.StatusFound)
+			},
+			wantRequests: 10,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			requests := 0
+			server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				requests++
+				tt.handler(w, r)
+			}))
+			// Silence the TLS server's handshake error log
+			server.Config.ErrorLog = log.New(io.Discard, "", 0)
+			if tt.tls {
+				server.StartTLS()
+			} else {
+				server.Start()
+			}
+			defer server.Close()
+
+			breaker := NewCircuitBreaker(1, time.Minute)
+			fetcher := NewRSSFetcher(FetcherOptions{
+				Timeout: 5 * time.Second,
+				Retry:   RetryPolicy{MaxAttempts: 3},
+				Breaker: breaker,
+			})
+			fetcher.sleep = func(context.Context, time.Duration) error { return nil }
+
+			_, err := fetcher.FetchRSS(context.Background(), server.URL)
+			var fetchErr *errors.FetchError
+			if !stderrors.As(err, &fetchErr) || fetchErr.Attempts != 1 {
+				t.Fatalf("FetchRSS() error = %v, want FetchError after 1 attempt", err)
+			}
+			if requests != tt.wantRequests {
+				t.Errorf("requests = %d, want %d", requests, tt.wantRequests)
+			}
+			if got := breaker.Status(); len(got) != 0 {
+				t.Errorf("breaker Status() = %+v, want no failures recorded", got)
+			}
+		})
+	}
+}
+
+func TestRSSFetcher_FetchRSS_CircuitBreaker(t *testing.T) {
+	status := http.StatusBadGateway
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if status != http.StatusOK {
+			w.WriteHeader(status)
+			return
+		}
+		w.Write([]byte(testFeed))
+	}))
+	defer server.Close()
+
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	breaker := NewCircuitBreaker(2, time.Minute)
+	breaker.now = func() time.Time { return now }
+	fetcher := NewRSSFetcher(FetcherOptions{
+		Timeout: 5 * time.Second,
+		Retry:   RetryPolicy{MaxAttempts: 3},
+		Breaker: breaker,
+	})
+	fetcher.sleep = func(context.Context, time.Duration) error { return nil }
+
+	// Each fetch counts as one failure however many attempts it makes, so
+	// the breaker opens after the second fetch rather than during the first
+	var circuitOpen *errors.CircuitOpenError
+	for i := range 2 {
+		_, err := fetcher.FetchRSS(context.Background(), server.URL)
+		var fetchErr *errors.FetchError
+		if !stderrors.As(err, &fetchErr) {
+			t.Fatalf("FetchRSS() #%d error = %v, want FetchError", i+1, err)
+		}
+	}
+	if requests != 6 {
+		t.Errorf("requests = %d, want 6", requests)
+	}
+
+	// Further fetches fail fast without contacting the host
+	if _, err := fetcher.FetchRSS(context.Background(), server.URL); !stderrors.As(err, &circuitOpen) {
+		t.Errorf("FetchRSS() error = %v, want CircuitOpenError", err)
+	}
+	if requests != 6 {
+		t.Errorf("requests = %d, want no request while open", requests)
+	}
+
+	// Once the host recovers the half-open probe closes the breaker
+	status = http.StatusOK
+	now = now.Add(time.Minute)
+	if _, err := fetcher.FetchRSS(context.Background(), server.URL); err != nil {
+		t.Fatalf("FetchRSS() error = %v", err)
+	}
+	if got := breaker.Status(); len(got) != 0 {
+		t.Errorf("Status() = %+v, want closed", got)
+	}
+
+	// Client errors mean the host is up and do not trip the breaker
+	status = http.StatusNotFound
+	for range 3 {
+		fetcher.FetchRSS(context.Background(), server.URL)
+	}
+	if err := breaker.Allow(requestHost(server.URL)); err != nil {
+		t.Errorf("Allow() error = %v, want 404s ignored", err)
+	}
+}
+
+func TestRSSFetcher_FetchRSS_CircuitBreakerPerPort(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(testFeed))
+	}))
+	defer server.Close()
+
+	// A port on the same host where nothing listens
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadURL := "http://" + listener.Addr().String() + "/feed"
+	listener.Close()
+
+	breaker := NewCircuitBreaker(1, time.Minute)
+	fetcher := NewRSSFetcher(FetcherOptions{Timeout: 5 * time.Second, Breaker: breaker})
+
+	if _, err := fetcher.FetchRSS(context.Background(), deadURL); err == nil {
+		t.Fatal("FetchRSS() on a closed port succeeded")
+	}
+	var circuitOpen *errors.CircuitOpenError
+	if _, err := fetcher.FetchRSS(context.Background(), deadURL); !stderrors.As(err, &circuitOpen) {
+		t.Errorf("FetchRSS() error = %v, want CircuitOpenError for the failing port", err)
+	}
+
+	// The failing port does not open the breaker of the host's other ports
+	if _, err := fetcher.FetchRSS(context.Background(), server.URL); err != nil {
+		t.Errorf("FetchRSS() error = %v, want the working port unaffected", err)
+	}
+}
+
+func TestRequestHost(t *testing.T) {
+	tests := map[string]string{
+		"http://Example.com/feed":        "Example.com:80",
+		"https://example.com/feed":       "example.com:443",
+		"http://example.com:81/feed":     "example.com:81",
+		"https://[2001:db8::1]:8443/rss": "[2001:db8::1]:8443",
+	}
+	for url, want := range tests {
+		if got := requestHost(url); got != want {
+			t.Errorf("requestHost(%q) = %q, want %q", url, got, want)
+		}
+	}
+}
+
+func TestRSSFetcher_FetchFeed_Discovery(t *testing.T) {
+	tests := []struct {
+		name     string
+		page     string
+		feeds    map[string]string
+		wantPath string
+		wantErr  error
+	}{
+		{
+			name: "linked feed",
+			page: `<!DOCTYPE html><html><head><link rel="alternate" type="application/rss+xml" href="/blog/rss"></head></html>`,
+			feeds: map[string]string{
+				"/blog/rss": testFeed,
+			},
+			wantPath: "/blog/rss",
+		},
+		{
+			name: "falls back to common paths",
+			page: `<!DOCTYPE html><html><head><link rel="alternate" type="application/rss+xml" href="/missing"></head></html>`,
+			feeds: map[string]string{
+				"/feed":    `<!DOCTYPE html><html><body>Not a feed</body></html>`,
+				"/rss.xml": testFeed,
+			},
+			wantPath: "/rss.xml",
+		},
+		{
+			name:    "no feed found",
+			page:    `<!DOCTYPE html><html><body>Hello</body></html>`,
+			wantErr: errors.ErrNoFeedFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/" {
+					w.Header().Set("Content-Type", "text/html; charset=utf-8")
+					w.Write([]byte(tt.page))
+					return
+				}
+				feed, ok := tt.feeds[r.URL.Path]
+				if !ok {
+					http.NotFound(w, r)
+					return
+				}
+				w.Write([]byte(feed))
+			}))
+			defer server.Close()
+
+			fetcher := NewRSSFetcher(FetcherOptions{Timeout: 5 * time.Second, UserAgent: "test-agent"})
+			result, err := fetcher.FetchFeed(context.Background(), server.URL+"/", ParseOptions{})
+			if tt.wantErr != nil {
+				if !stderrors.Is(err, tt.wantErr) {
+					t.Fatalf("FetchFeed() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("FetchFeed() error = %v", err)
+			}
+			if want := server.URL + tt.wantPath; result.FeedURL != want {
+				t.Errorf("FeedURL = %q, want %q", result.FeedURL, want)
+			}
+			if len(result.RSS.Channel.Items) != 1 {
+				t.Errorf("len(Items) = %d, want 1", len(result.RSS.Channel.Items))
+			}
+		})
+	}
+}
+
+func TestRSSFetcher_FetchFeed_DiscoveryBudget(t *testing.T) {
+	page := `<!DOCTYPE html><html><head><link rel="alternate" type="application/rss+xml" href="/flaky"></head></html>`
+
+	t.Run("candidates are not retried", func(t *testing.T) {
+		requests := make(map[string]int)
+		var mu sync.Mutex
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			requests[r.URL.Path]++
+			mu.Unlock()
+			switch r.URL.Path {
+			case "/":
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Write([]byte(page))
+			case "/flaky":
+				w.WriteHeader(http.StatusServiceUnavailable)
+			case "/rss.xml":
+				w.Write([]byte(testFeed))
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		fetcher := NewRSSFetcher(FetcherOptions{Timeout: 5 * time.Second, Retry: RetryPolicy{MaxAttempts: 3}})
+		fetcher.sleep = func(context.Context, time.Duration) error { return nil }
+		result, err := fetcher.FetchFeed(context.Background(), server.URL+"/", ParseOptions{})
+		if err != nil {
+			t.Fatalf("FetchFeed() error = %v", err)
+		}
+		if want := server.URL + "/rss.xml"; result.FeedURL != want {
+			t.Errorf("FeedURL = %q, want %q", result.FeedURL, want)
+		}
+		for _, path := range []string{"/flaky", "/feed"} {
+			if requests[path] != 1 {
+				t.Errorf("requests to %s = %d, want 1", path, requests[path])
+			}
+		}
+	})
+
+	t.Run("one deadline covers discovery", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/" {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.Write([]byte(page))
+				return
+			}
+			// Every candidate is slow, so trying them all would take seconds
+			select {
+			case <-time.After(500 * time.Millisecond):
+			case <-r.Context().Done():
+			}
+			http.NotFound(w, r)
+		}))
+		defer server.Close()
+
+		fetcher := NewRSSFetcher(FetcherOptions{Timeout: 5 * time.Second, Retry: RetryPolicy{MaxAttempts: 3, Deadline: 300 * time.Millisecond}})
+		start := time.Now()
+		_, err := fetcher.FetchFeed(context.Background(), server.URL+"/", ParseOptions{})
+		if err == nil {
+			t.Fatal("FetchFeed() succeeded, want deadline error")
+		}
+		if elapsed := time.Since(start); elapsed > 2*time.Second {
+			t.Errorf("FetchFeed() took %v, want it bounded by the 300ms deadline", elapsed)
+		}
+	})
+}
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"cmp"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+)
+
+// SortKey orders exported items by one column
+type SortKey struct {
+	Column     Column
+	Descending bool
+}
+
+// ParseSort parses a sort order such as "pubDate:desc,title". Each entry
+// names a field, optionally followed by :asc (the default) or :desc; a
+// leading "-" also selects descending order. "date" is short for pubDate.
+func ParseSort(spec string) ([]SortKey, error) {
+	var keys []SortKey
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		var key SortKey
+		field, direction, _ := strings.Cut(entry, ":")
+		field = strings.TrimSpace(field)
+		if rest, ok := strings.CutPrefix(field, "-"); ok {
+			field, key.Descending = rest, true
+		}
+		switch strings.ToLower(strings.TrimSpace(direction)) {
+		case "", "asc":
+		case "desc":
+			key.Descending = true
+		default:
+			return nil, &errors.ValidationError{
+				Field:   "sort",
+				Message: fmt.Sprintf("invalid direction %q for field %q: must be asc or desc", direction, field),
+			}
+		}
+
+		if strings.EqualFold(field, "date") {
+			field = "pubDate"
+		}
+		column, ok := LookupColumn(field)
+		if !ok {
+			return nil, &errors.ValidationError{
+				Field:   "sort",
+				Message: fmt.Sprintf("unknown field %q", field),
+			}
+		}
+		key.Column = column
+		keys = append(keys, key)
+	}
+
+	if len(keys) == 0 {
+		return nil, &errors.ValidationError{
+			Field:   "sort",
+			Message: "at least one field must be given",
+		}
+	}
+	return keys, nil
+}
+
+// sortValue is the value of an item's sort key, extracted once before sorting
+type sortValue struct {
+	text string
+	date time.Time
+}
+
+// sortItems orders items by the keys. The sort is stable, so items that
+// compare equal keep the feed's order. Date columns compare by their
+// parsed date and undated items come last in either direction. Other
+// columns put numeric values first, compared as numbers, followed by the
+// rest compared as text, ignoring case.
+func sortItems(items []*models.Item, channel *models.Channel, keys []SortKey) {
+	values := make(map[*models.Item][]sortValue, len(items))
+	for _, item := range items {
+		row := make([]sortValue, len(keys))
+		for i, key := range keys {
+			if key.Column.date != nil {
+				row[i].date = key.Column.date(item, channel)
+			} else {
+				row[i].text = strings.ToLower(strings.TrimSpace(key.Column.Value(item, channel, DefaultMultiValueSeparator)))
+			}
+		}
+		values[item] = row
+	}
+
+	sort.SliceStable(items, func(a, b int) bool {
+		va, vb := values[items[a]], values[items[b]]
+		for i, key := range keys {
+			order := compareSortValues(va[i], vb[i], key.Column.date != nil)
+			if order == 0 {
+				continue
+			}
+			// Missing dates stay last whatever the direction
+			if key.Column.date != nil && (va[i].date.IsZero() || vb[i].date.IsZero()) {
+				return vb[i].date.IsZero()
+			}
+			if key.Descending {
+				return order > 0
+			}
+			return order < 0
+		}
+		return false
+	})
+}
+
+// compareSortValues compares two sort values, returning -1, 0 or 1
+func compareSortValues(a, b sortValue, isDate bool) int {
+	if isDate {
+		return a.date.Compare(b.date)
+	}
+	x, errX := strconv.ParseFloat(a.text, 64)
+	y, errY := strconv.ParseFloat(b.text, 64)
+	switch {
+	case errX == nil && errY == nil:
+		return cmp.Compare(x, y)
+	case errX == nil:
+		// Numbers sort before text so the order stays total
+		return -1
+	case errY == nil:
+		return 1
+	}
+	return strings.Compare(a.text, b.text)
+}
@@ -0,0 +1,136 @@
+package services
+
+import (
+	stderrors "errors"
+	"strings"
+	"testing"
+	"time"
+
+	"rss-feed-to-csv/internal/errors"
+	"rss-feed-to-csv/internal/models"
+)
+
+func TestParseSort(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		wantKeys []string
+		wantErr  bool
+	}{
+		{name: "default ascending", spec: "title", wantKeys: []string{"title asc"}},
+		{name: "directions", spec: "pubDate:DESC, link:asc", wantKeys: []string{"pubDate desc", "link asc"}},
+		{name: "minus prefix and date alias", spec: "-date", wantKeys: []string{"pubDate desc"}},
+		{name: "unknown field", spec: "bogus", wantErr: true},
+		{name: "bad direction", spec: "title:up", wantErr: true},
+		{name: "no fields", spec: " , ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keys, err := ParseSort(tt.spec)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseSort() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				var validationErr *errors.ValidationError
+				if !stderrors.As(err, &validationErr) || validationErr.Field != "sort" {
+					t.Errorf("ParseSort() error = %v, want ValidationError on sort", err)
+				}
+				return
+			}
+			if len(keys) != len(tt.wantKeys) {
+				t.Fatalf("ParseSort() = %d keys, want %d", len(keys), len(tt.wantKeys))
+			}
+			for i, key := range keys {
+				got := key.Column.Field + " asc"
+				if key.Descending {
+					got = key.Column.Field + " desc"
+				}
+				if got != tt.wantKeys[i] {
+					t.Errorf("key %d = %s, want %s", i, got, tt.wantKeys[i])
+				}
+			}
+		})
+	}
+}
+
+func TestExportOptions_RowsSortLimitOffset(t *testing.T) {
+	day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rss := &models.RSS{Channel: models.Channel{Items: []models.Item{
+		{Title: "b", Link: "10", PublishedAt: day},
+		{Title: "undated", Link: "9"},
+		{Title: "a", Link: "2", PublishedAt: day.AddDate(0, 0, 2)},
+		{Title: "B", Link: "1", PublishedAt: day.AddDate(0, 0, 1)},
+	}}}
+
+	tests := []struct {
+		name   string
+		sort   string
+		offset int
+		limit  int
+		want   []string
+	}{
+		{name: "feed order", want: []string{"b", "undated", "a", "B"}},
+		{name: "newest first, undated last", sort: "pubDate:desc", want: []string{"a", "B", "b", "undated"}},
+		{name: "oldest first, undated last", sort: "pubDate", want: []string{"b", "B", "a", "undated"}},
+		{name: "text ignores case and is stable", sort: "title", want: []string{"a", "b", "B", "undated"}},
+		{name: "numbers compare numerically", sort: "link", want: []string{"B", "a", "undated", "b"}},
+		{name: "secondary key", sort: "title:desc,pubDate:desc", want: []string{"undated", "B", "b", "a"}},
+		{name: "limit", sort: "-date", limit: 2, want: []string{"a", "B"}},
+		{name: "offset and limit", sort: "-date", offset: 1, limit: 2, want: []string{"B", "b"}},
+		{name: "offset past the end", offset: 10, want: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			opts := ExportOptions{Offset: tt.offset, Limit: tt.limit}
+			if tt.sort != "" {
+				keys, err := ParseSort(tt.sort)
+				if err != nil {
+					t.Fatalf("ParseSort() error = %v", err)
+				}
+				opts.Sort = keys
+			}
+
+			rows := opts.rows(rss)
+			if len(rows) != len(tt.want) {
+				t.Fatalf("rows() = %d items, want %v", len(rows), tt.want)
+			}
+			if count := opts.RowCount(rss); count != len(rows) {
+				t.Errorf("RowCount() = %d, want %d", count, len(rows))
+			}
+			for i, item := range rows {
+				if item.Title != tt.want[i] {
+					t.Errorf("row %d = %q, want %q", i, item.Title, tt.want[i])
+				}
+			}
+		})
+	}
+
+	// Sorting works on the rows, not the feed itself
+	if rss.Channel.Items[0].Title != "b" || rss.Channel.Items[3].Title != "B" {
+		t.Error("rows() reordered the feed's items")
+	}
+}
+
+func TestExportOptions_RowsSortMixedValues(t *testing.T) {
+	keys, err := ParseSort("title")
+	if err != nil {
+		t.Fatalf("ParseSort() error = %v", err)
+	}
+
+	// Numbers come before text whatever the input order
+	for _, titles := range [][]string{{"10", "9", "1a"}, {"1a", "9", "10"}, {"9", "1a", "10"}} {
+		mixed := &models.RSS{}
+		for _, title := range titles {
+			mixed.Channel.Items = append(mixed.Channel.Items, models.Item{Title: title})
+		}
+		var got []string
+		for _, item := range (ExportOptions{Sort: keys}).rows(mixed) {
+			got = append(got, item.Title)
+		}
+		if strings.Join(got, " ") != "9 10 1a" {
+			t.Errorf("rows() sorted %q as %q, want [9 10 1a]", titles, got)
+		}
+	}
+}
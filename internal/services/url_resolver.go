@@ -0,0 +1,126 @@
+package services
+
+import (
+	"html"
+	neturl "net/url"
+	"regexp"
+	"strings"
+
+	"rss-feed-to-csv/internal/models"
+)
+
+var (
+	htmlStartTagPattern = regexp.MustCompile(`<[a-zA-Z][^>]*>`)
+	htmlURLAttrPattern  = regexp.MustCompile(`(?i)(\s(?:href|src|poster)\s*=\s*)("[^"]*"|'[^']*'|[^\s"'>]+)`)
+)
+
+// resolveURLs makes the URLs of a feed absolute. Channel URLs are resolved
+// against the xml:base of the document, falling back to the feed URL. Item
+// URLs are resolved against the item's xml:base on top of the document's,
+// or the channel link if the document has none. URLs inside descriptions
+// and content are only rewritten when resolveContent is set.
+func resolveURLs(rss *models.RSS, feedURL string, resolveContent bool) {
+	base, err := neturl.Parse(feedURL)
+	if err != nil || !base.IsAbs() {
+		base = nil
+	}
+	base = withBase(base, rss.Base)
+	base = withBase(base, rss.Channel.Base)
+
+	channel := &rss.Channel
+	for i := range channel.Links {
+		link := &channel.Links[i]
+		link.Value = resolveURL(base, link.Value)
+		link.Href = resolveURL(base, link.Href)
+	}
+	channel.Image.URL = resolveURL(base, channel.Image.URL)
+	channel.Image.Link = resolveURL(base, channel.Image.Link)
+
+	// Plain RSS has no xml:base, but its relative links are relative to
+	// the website the channel links to
+	itemBase := base
+	if rss.Base == "" && channel.Base == "" {
+		if link, err := neturl.Parse(channel.GetLink()); err == nil && link.IsAbs() {
+			itemBase = link
+		}
+	}
+
+	for i := range channel.Items {
+		item := &channel.Items[i]
+		ref := withBase(itemBase, item.Base)
+
+		item.Link = resolveURL(ref, item.Link)
+		item.Comments = models.PlainText(resolveURL(ref, string(item.Comments)))
+		item.Source.URL = resolveURL(ref, item.Source.URL)
+		for j := range item.Enclosures {
+			item.Enclosures[j].URL = resolveURL(ref, item.Enclosures[j].URL)
+		}
+		for j := range item.MediaContent {
+			item.MediaContent[j].URL = resolveURL(ref, item.MediaContent[j].URL)
+		}
+		if resolveContent {
+			item.Description = resolveHTMLURLs(ref, item.Description)
+			item.ContentEncoded = resolveHTMLURLs(ref, item.ContentEncoded)
+		}
+	}
+}
+
+// withBase applies an xml:base attribute, which may itself be relative, to a base URL
+func withBase(base *neturl.URL, xmlBase string) *neturl.URL {
+	xmlBase = strings.TrimSpace(xmlBase)
+	if xmlBase == "" {
+		return base
+	}
+	ref, err := neturl.Parse(xmlBase)
+	if err != nil {
+		return base
+	}
+	if base != nil {
+		return base.ResolveReference(ref)
+	}
+	if ref.IsAbs() {
+		return ref
+	}
+	return nil
+}
+
+// resolveURL resolves a possibly relative URL against a base. Absolute URLs,
+// empty values and values that are not URLs are returned unchanged.
+func resolveURL(base *neturl.URL, value string) string {
+	trimmed := strings.TrimSpace(value)
+	if base == nil || trimmed == "" {
+		return value
+	}
+	ref, err := neturl.Parse(trimmed)
+	if err != nil || ref.IsAbs() {
+		return value
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// resolveHTMLURLs resolves the href, src and poster attributes of the tags
+// in an HTML fragment. Fragment-only links are left alone, since they point
+// into the document itself.
+func resolveHTMLURLs(base *neturl.URL, fragment string) string {
+	if base == nil || !strings.Contains(fragment, "<") {
+		return fragment
+	}
+	return htmlStartTagPattern.ReplaceAllStringFunc(fragment, func(tag string) string {
+		return htmlURLAttrPattern.ReplaceAllStringFunc(tag, func(attr string) string {
+			match := htmlURLAttrPattern.FindStringSubmatch(attr)
+			prefix, value := match[1], match[2]
+			if value[0] == '"' || value[0] == '\'' {
+				value = value[1 : len(value)-1]
+			}
+			value = html.UnescapeString(value)
+			if strings.HasPrefix(value, "#") {
+				return attr
+			}
+			resolved := resolveURL(base, value)
+			if resolved == value {
+				return attr
+			}
+			return prefix + `"` + html.EscapeString(resolved) + `"`
+		})
+	})
+}
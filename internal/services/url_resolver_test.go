@@ -0,0 +1,130 @@
+package services
+
+import (
+	neturl "net/url"
+	"testing"
+
+	"rss-feed-to-csv/internal/models"
+)
+
+func TestResolveURLs(t *testing.T) {
+	tests := []struct {
+		name     string
+		body     string
+		feedURL  string
+		wantLink string
+		wantImg  string
+	}{
+		{
+			name: "RSS against channel link",
+			body: `<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/"><channel><link>https://www.example.com/blog/</link>
+				<item><title>One</title><link>posts/1</link><media:content url="/img/1.png" medium="image"/></item>
+			</channel></rss>`,
+			feedURL:  "https://feeds.example.net/blog.xml",
+			wantLink: "https://www.example.com/blog/posts/1",
+			wantImg:  "https://www.example.com/img/1.png",
+		},
+		{
+			name: "RSS without channel link uses feed URL",
+			body: `<rss version="2.0"><channel>
+				<item><title>One</title><link>../posts/1</link><enclosure url="1.jpg" type="image/jpeg" length="1"/></item>
+			</channel></rss>`,
+			feedURL:  "https://example.com/feeds/main/rss.xml",
+			wantLink: "https://example.com/feeds/posts/1",
+			wantImg:  "https://example.com/feeds/main/1.jpg",
+		},
+		{
+			name: "Atom xml:base on feed and entry",
+			body: `<feed xmlns="http://www.w3.org/2005/Atom" xml:base="https://example.org/site/">
+				<link href="/"/>
+				<entry xml:base="2024/"><title>One</title><link href="post.html"/><link rel="enclosure" type="image/png" href="//cdn.example.org/a.png"/></entry>
+			</feed>`,
+			feedURL:  "https://example.org/atom.xml",
+			wantLink: "https://example.org/site/2024/post.html",
+			wantImg:  "https://cdn.example.org/a.png",
+		},
+		{
+			name: "absolute URLs are kept",
+			body: `<rss version="2.0"><channel><link>https://example.com/</link>
+				<item><title>One</title><link>https://other.example/1?a=b</link><enclosure url="mailto:x@example.com" type="image/png"/></item>
+			</channel></rss>`,
+			feedURL:  "https://example.com/rss",
+			wantLink: "https://other.example/1?a=b",
+			wantImg:  "mailto:x@example.com",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rss, err := ParseFeed([]byte(tt.body), "")
+			if err != nil {
+				t.Fatalf("ParseFeed() error = %v", err)
+			}
+			resolveURLs(rss, tt.feedURL, false)
+
+			item := &rss.Channel.Items[0]
+			if item.Link != tt.wantLink {
+				t.Errorf("Link = %q, want %q", item.Link, tt.wantLink)
+			}
+			if got := item.GetImageURL(); got != tt.wantImg {
+				t.Errorf("GetImageURL() = %q, want %q", got, tt.wantImg)
+			}
+		})
+	}
+}
+
+func TestResolveURLs_Content(t *testing.T) {
+	description := `<p><a href="/about">About</a> <a href='#top'>Top</a> <img src=img/a.png alt="x"> <a href="https://other.example/">Other</a></p>`
+	newRSS := func() *models.RSS {
+		return &models.RSS{Channel: models.Channel{Items: []models.Item{{
+			Link:           "post",
+			Description:    description,
+			ContentEncoded: `<img src="pic.jpg?w=1&amp;h=2">`,
+		}}}}
+	}
+
+	rss := newRSS()
+	resolveURLs(rss, "https://example.com/blog/feed", false)
+	if rss.Channel.Items[0].Description != description {
+		t.Errorf("Description changed without resolveContent: %q", rss.Channel.Items[0].Description)
+	}
+
+	rss = newRSS()
+	resolveURLs(rss, "https://example.com/blog/feed", true)
+	item := rss.Channel.Items[0]
+	wantDescription := `<p><a href="https://example.com/about">About</a> <a href='#top'>Top</a> <img src="https://example.com/blog/img/a.png" alt="x"> <a href="https://other.example/">Other</a></p>`
+	if item.Description != wantDescription {
+		t.Errorf("Description = %q, want %q", item.Description, wantDescription)
+	}
+	if want := `<img src="https://example.com/blog/pic.jpg?w=1&amp;h=2">`; item.ContentEncoded != want {
+		t.Errorf("ContentEncoded = %q, want %q", item.ContentEncoded, want)
+	}
+	if want := "https://example.com/blog/post"; item.Link != want {
+		t.Errorf("Link = %q, want %q", item.Link, want)
+	}
+}
+
+func TestWithBase(t *testing.T) {
+	feed, _ := neturl.Parse("https://example.com/a/feed.xml")
+	tests := []struct {
+		base    *neturl.URL
+		xmlBase string
+		want    string
+	}{
+		{feed, "", "https://example.com/a/feed.xml"},
+		{feed, "sub/", "https://example.com/a/sub/"},
+		{feed, "https://other.example/", "https://other.example/"},
+		{nil, "https://other.example/x/", "https://other.example/x/"},
+		{nil, "relative/", ""},
+	}
+
+	for _, tt := range tests {
+		got := ""
+		if resolved := withBase(tt.base, tt.xmlBase); resolved != nil {
+			got = resolved.String()
+		}
+		if got != tt.want {
+			t.Errorf("withBase(%v, %q) = %q, want %q", tt.base, tt.xmlBase, got, tt.want)
+		}
+	}
+}
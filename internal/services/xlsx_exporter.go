@@ -0,0 +1,385 @@
+package services
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"rss-feed-to-csv/internal/models"
+	"rss-feed-to-csv/internal/utils"
+)
+
+const (
+	// xlsxMaxCellLength is the maximum number of characters Excel accepts in a cell
+	xlsxMaxCellLength = 32767
+	// xlsxMaxHyperlinkLength is the longest URL Excel can store as a hyperlink
+	xlsxMaxHyperlinkLength = 2079
+	// xlsxMaxHyperlinks is the maximum number of hyperlinks per worksheet
+	xlsxMaxHyperlinks = 65530
+	// xlsxMinColumnWidth and xlsxMaxColumnWidth bound the auto-fitted column widths
+	xlsxMinColumnWidth = 8
+	xlsxMaxColumnWidth = 80
+	// xlsxDateColumnWidth fits the yyyy-mm-dd hh:mm:ss date format
+	xlsxDateColumnWidth = 20
+)
+
+// Cell style indexes into the cellXfs table of xlsxStyles
+const (
+	xlsxStyleDefault = iota
+	xlsxStyleHeader
+	xlsxStyleDate
+	xlsxStyleHyperlink
+)
+
+// xlsxEpoch is day zero of Excel's 1900 date system
+var xlsxEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// XLSXExporter handles exporting RSS data to Excel workbooks
+type XLSXExporter struct {
+	sanitizer *utils.HTMLSanitizer
+}
+
+// NewXLSXExporter creates a new XLSX exporter
+func NewXLSXExporter() *XLSXExporter {
+	return &XLSXExporter{
+		sanitizer: utils.NewHTMLSanitizer(),
+	}
+}
+
+// xlsxPart is a single file inside the workbook package
+type xlsxPart struct {
+	name    string
+	content string
+}
+
+// xlsxSheet is a worksheet and the rendered cells it holds
+type xlsxSheet struct {
+	name    string
+	columns []Column
+	rows    [][]string
+}
+
+// xlsxHyperlink is a hyperlink from a cell to an external URL
+type xlsxHyperlink struct {
+	ref string
+	url string
+}
+
+// ContentType returns the media type of XLSX output
+func (e *XLSXExporter) ContentType() string {
+	return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+}
+
+// FileExtension returns the file extension for XLSX downloads
+func (e *XLSXExporter) FileExtension() string {
+	return "xlsx"
+}
+
+// Export writes RSS items, or a channel summary, as an XLSX workbook. A batch
+// summary goes on a second sheet. Dates become real date cells, URLs become
+// hyperlinks and the header row is frozen.
+func (e *XLSXExporter) Export(ctx context.Context, w io.Writer, rss *models.RSS, opts ExportOptions) error {
+	// Date cells hold no zone, so reformatted dates are always written as
+	// wall-clock time in the target zone, whatever text format was chosen
+	if opts.dateFormat() != DateRaw {
+		opts.DateFormat = DateExcel
+	}
+	columns := opts.columns()
+	items := opts.rows(rss)
+
+	// Render every cell up front; column widths must be written before the data
+	rows := make([][]string, len(items))
+	for i, item := range items {
+		rows[i] = make([]string, len(columns))
+		for j, column := range columns {
+			rows[i][j] = opts.cellValue(e.sanitizer, column, item, &rss.Channel)
+		}
+	}
+
+	sheetName := "Items"
+	if opts.Mode == ModeChannel {
+		sheetName = "Channel"
+	}
+	sheets := []xlsxSheet{{name: sheetName, columns: columns, rows: rows}}
+	if len(opts.Summary) > 0 {
+		sheets = append(sheets, summarySheet(opts.Summary))
+	}
+
+	parts := []xlsxPart{
+		{"[Content_Types].xml", fmt.Sprintf(xlsxContentTypes, sheetOverrides(len(sheets)))},
+		{"_rels/.rels", xlsxRootRels},
+		{"xl/workbook.xml", fmt.Sprintf(xlsxWorkbook, workbookSheets(sheets))},
+		{"xl/_rels/workbook.xml.rels", fmt.Sprintf(xlsxWorkbookRels, workbookSheetRels(len(sheets)), len(sheets)+1)},
+		{"xl/styles.xml", xlsxStyles},
+	}
+	for i, sheet := range sheets {
+		content, links := e.buildSheet(sheet.columns, sheet.rows)
+		parts = append(parts, xlsxPart{fmt.Sprintf("xl/worksheets/sheet%d.xml", i+1), content})
+		if len(links) > 0 {
+			parts = append(parts, xlsxPart{fmt.Sprintf("xl/worksheets/_rels/sheet%d.xml.rels", i+1), buildSheetRels(links)})
+		}
+	}
+
+	zw := zip.NewWriter(w)
+	for _, part := range parts {
+		fw, err := zw.Create(part.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, part.content); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// buildSheet renders the worksheet XML and collects the hyperlinks it references
+func (e *XLSXExporter) buildSheet(columns []Column, rows [][]string) (string, []xlsxHyperlink) {
+	var links []xlsxHyperlink
+	var sb strings.Builder
+
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+
+	// Freeze the header row
+	sb.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/></sheetView></sheetViews>`)
+
+	sb.WriteString(`<cols>`)
+	for i, width := range columnWidths(columns, rows) {
+		fmt.Fprintf(&sb, `<col min="%d" max="%d" width="%d" customWidth="1"/>`, i+1, i+1, width)
+	}
+	sb.WriteString(`</cols>`)
+
+	sb.WriteString(`<sheetData>`)
+	sb.WriteString(`<row r="1">`)
+	for i, column := range columns {
+		writeStringCell(&sb, cellRef(i, 1), column.Header, xlsxStyleHeader)
+	}
+	sb.WriteString(`</row>`)
+
+	for r, row := range rows {
+		rowNum := r + 2
+		fmt.Fprintf(&sb, `<row r="%d">`, rowNum)
+		for i, value := range row {
+			if value == "" {
+				continue
+			}
+			ref := cellRef(i, rowNum)
+			switch columns[i].Kind {
+			case KindDate:
+				if t, ok := utils.ParseDate(value); ok {
+					fmt.Fprintf(&sb, `<c r="%s" s="%d"><v>%s</v></c>`, ref, xlsxStyleDate, excelSerial(t))
+					continue
+				}
+			case KindURL:
+				if isHyperlinkable(value) && len(links) < xlsxMaxHyperlinks {
+					links = append(links, xlsxHyperlink{ref: ref, url: value})
+					writeStringCell(&sb, ref, value, xlsxStyleHyperlink)
+					continue
+				}
+			}
+			writeStringCell(&sb, ref, value, xlsxStyleDefault)
+		}
+		sb.WriteString(`</row>`)
+	}
+	sb.WriteString(`</sheetData>`)
+
+	if len(links) > 0 {
+		sb.WriteString(`<hyperlinks>`)
+		for i, link := range links {
+			fmt.Fprintf(&sb, `<hyperlink ref="%s" r:id="rId%d"/>`, link.ref, i+1)
+		}
+		sb.WriteString(`</hyperlinks>`)
+	}
+
+	sb.WriteString(`</worksheet>`)
+	return sb.String(), links
+}
+
+// summarySheet builds the sheet that reports the outcome of every feed of a batch export
+func summarySheet(reports []FeedReport) xlsxSheet {
+	columns := make([]Column, len(summaryHeaders))
+	for i, header := range summaryHeaders {
+		columns[i] = Column{Header: header}
+	}
+	columns[0].Kind = KindURL
+
+	rows := make([][]string, len(reports))
+	for i, report := range reports {
+		rows[i] = report.record()
+	}
+	return xlsxSheet{name: "Summary", columns: columns, rows: rows}
+}
+
+// sheetOverrides lists the content type of every worksheet part
+func sheetOverrides(count int) string {
+	var sb strings.Builder
+	for i := 1; i <= count; i++ {
+		fmt.Fprintf(&sb, `<Override PartName="/xl/worksheets/sheet%d.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>`, i)
+	}
+	return sb.String()
+}
+
+// workbookSheets lists the worksheets of the workbook in tab order
+func workbookSheets(sheets []xlsxSheet) string {
+	var sb strings.Builder
+	for i, sheet := range sheets {
+		fmt.Fprintf(&sb, `<sheet name="%s" sheetId="%d" r:id="rId%d"/>`, xmlEscape(sheet.name), i+1, i+1)
+	}
+	return sb.String()
+}
+
+// workbookSheetRels links the workbook to its worksheet parts
+func workbookSheetRels(count int) string {
+	var sb strings.Builder
+	for i := 1; i <= count; i++ {
+		fmt.Fprintf(&sb, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet%d.xml"/>`, i, i)
+	}
+	return sb.String()
+}
+
+// buildSheetRels renders the relationships that back the sheet's hyperlinks
+func buildSheetRels(links []xlsxHyperlink) string {
+	var sb strings.Builder
+	sb.WriteString(xml.Header)
+	sb.WriteString(`<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">`)
+	for i, link := range links {
+		fmt.Fprintf(&sb, `<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/hyperlink" Target="%s" TargetMode="External"/>`,
+			i+1, xmlEscape(link.url))
+	}
+	sb.WriteString(`</Relationships>`)
+	return sb.String()
+}
+
+// writeStringCell writes an inline string cell, truncated to Excel's cell limit
+func writeStringCell(sb *strings.Builder, ref, value string, style int) {
+	fmt.Fprintf(sb, `<c r="%s" t="inlineStr"`, ref)
+	if style != xlsxStyleDefault {
+		fmt.Fprintf(sb, ` s="%d"`, style)
+	}
+	sb.WriteString(`><is><t xml:space="preserve">`)
+	sb.WriteString(xmlEscape(truncateCell(value)))
+	sb.WriteString(`</t></is></c>`)
+}
+
+// columnWidths estimates a width for each column from its longest line
+func columnWidths(columns []Column, rows [][]string) []int {
+	widths := make([]int, len(columns))
+	for i, column := range columns {
+		widths[i] = utf8.RuneCountInString(column.Header)
+		if column.Kind == KindDate && widths[i] < xlsxDateColumnWidth {
+			widths[i] = xlsxDateColumnWidth
+		}
+	}
+	for _, row := range rows {
+		for i, value := range row {
+			for _, line := range strings.Split(value, "\n") {
+				if n := utf8.RuneCountInString(line); n > widths[i] {
+					widths[i] = n
+				}
+				if widths[i] >= xlsxMaxColumnWidth {
+					break
+				}
+			}
+		}
+	}
+	for i := range widths {
+		widths[i] = min(max(widths[i]+2, xlsxMinColumnWidth), xlsxMaxColumnWidth)
+	}
+	return widths
+}
+
+// cellRef returns the A1-style reference for a zero-based column and one-based row
+func cellRef(col, row int) string {
+	name := ""
+	for col >= 0 {
+		name = string(rune('A'+col%26)) + name
+		col = col/26 - 1
+	}
+	return name + strconv.Itoa(row)
+}
+
+// excelSerial converts a time to an Excel serial date in UTC
+func excelSerial(t time.Time) string {
+	days := t.UTC().Sub(xlsxEpoch).Seconds() / 86400
+	return strconv.FormatFloat(days, 'f', -1, 64)
+}
+
+// isHyperlinkable reports whether a value can be stored as an external hyperlink
+func isHyperlinkable(value string) bool {
+	if len(value) > xlsxMaxHyperlinkLength {
+		return false
+	}
+	return strings.HasPrefix(value, "http://") || strings.HasPrefix(value, "https://")
+}
+
+// truncateCell shortens a value to Excel's limit, which counts UTF-16 code units
+func truncateCell(value string) string {
+	if len(value) <= xlsxMaxCellLength {
+		return value
+	}
+	units := 0
+	for i, r := range value {
+		units += len(utf16.Encode([]rune{r}))
+		if units > xlsxMaxCellLength {
+			return value[:i]
+		}
+	}
+	return value
+}
+
+// xmlEscape escapes text for use in XML content and attribute values.
+// Characters that are not allowed in XML are replaced with U+FFFD.
+func xmlEscape(value string) string {
+	var sb strings.Builder
+	xml.EscapeText(&sb, []byte(value))
+	return sb.String()
+}
+
+const xlsxContentTypes = xml.Header + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+	`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+	`<Default Extension="xml" ContentType="application/xml"/>` +
+	`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+	`%s` +
+	`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+	`</Types>`
+
+const xlsxRootRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+	`</Relationships>`
+
+const xlsxWorkbook = xml.Header + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+	`<sheets>%s</sheets>` +
+	`</workbook>`
+
+const xlsxWorkbookRels = xml.Header + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+	`%s` +
+	`<Relationship Id="rId%d" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+	`</Relationships>`
+
+// xlsxStyles defines the default, header (bold), date and hyperlink cell styles
+const xlsxStyles = xml.Header + `<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">` +
+	`<numFmts count="1"><numFmt numFmtId="164" formatCode="yyyy-mm-dd hh:mm:ss"/></numFmts>` +
+	`<fonts count="3">` +
+	`<font><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><b/><sz val="11"/><name val="Calibri"/></font>` +
+	`<font><u/><sz val="11"/><color rgb="FF0563C1"/><name val="Calibri"/></font>` +
+	`</fonts>` +
+	`<fills count="2"><fill><patternFill patternType="none"/></fill><fill><patternFill patternType="gray125"/></fill></fills>` +
+	`<borders count="1"><border><left/><right/><top/><bottom/><diagonal/></border></borders>` +
+	`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>` +
+	`<cellXfs count="4">` +
+	`<xf numFmtId="0" fontId="0" fillId="0" borderId="0" xfId="0"/>` +
+	`<xf numFmtId="0" fontId="1" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`<xf numFmtId="164" fontId="0" fillId="0" borderId="0" xfId="0" applyNumberFormat="1"/>` +
+	`<xf numFmtId="0" fontId="2" fillId="0" borderId="0" xfId="0" applyFont="1"/>` +
+	`</cellXfs>` +
+	`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>` +
+	`</styleSheet>`
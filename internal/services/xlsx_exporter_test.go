@@ -0,0 +1,172 @@
+package services
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/xml"
+	"io"
+	"strings"
+	"testing"
+
+	"rss-feed-to-csv/internal/models"
+)
+
+// readXLSX exports the feed and returns the workbook parts by name
+func readXLSX(t *testing.T, rss *models.RSS, opts ExportOptions) map[string]string {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := NewXLSXExporter().Export(context.Background(), &buf, rss, opts); err != nil {
+		t.Fatalf("Export() error = %v", err)
+	}
+
+	reader, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatalf("Failed to open XLSX as zip: %v", err)
+	}
+
+	parts := make(map[string]string)
+	for _, file := range reader.File {
+		rc, err := file.Open()
+		if err != nil {
+			t.Fatalf("Failed to open %s: %v", file.Name, err)
+		}
+		data, err := io.ReadAll(rc)
+		rc.Close()
+		if err != nil {
+			t.Fatalf("Failed to read %s: %v", file.Name, err)
+		}
+
+		// Every part must be well-formed XML
+		decoder := xml.NewDecoder(bytes.NewReader(data))
+		for {
+			if _, err := decoder.Token(); err == io.EOF {
+				break
+			} else if err != nil {
+				t.Fatalf("%s is not well-formed XML: %v", file.Name, err)
+			}
+		}
+		parts[file.Name] = string(data)
+	}
+	return parts
+}
+
+func TestXLSXExporter_Export(t *testing.T) {
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Items: []models.Item{
+				{
+					Title:       "Tom & Jerry <3",
+					Link:        "https://example.com/1?a=1&b=2",
+					Description: "<p>Hello</p>",
+					PubDate:     "Mon, 02 Jan 2006 15:04:05 +0000",
+				},
+				{
+					Title:   "Bad date",
+					Link:    "not a url",
+					PubDate: "yesterday",
+				},
+			},
+		},
+	}
+
+	parts := readXLSX(t, rss, ExportOptions{SanitizeHTML: true})
+
+	for _, name := range []string{
+		"[Content_Types].xml",
+		"_rels/.rels",
+		"xl/workbook.xml",
+		"xl/_rels/workbook.xml.rels",
+		"xl/styles.xml",
+		"xl/worksheets/sheet1.xml",
+		"xl/worksheets/_rels/sheet1.xml.rels",
+	} {
+		if _, ok := parts[name]; !ok {
+			t.Errorf("workbook is missing part %s", name)
+		}
+	}
+
+	sheet := parts["xl/worksheets/sheet1.xml"]
+	checks := []struct {
+		desc string
+		want string
+	}{
+		{"frozen header row", `<pane ySplit="1" topLeftCell="A2" activePane="bottomLeft" state="frozen"/>`},
+		{"bold header", `<c r="A1" t="inlineStr" s="1"><is><t xml:space="preserve">Title</t></is></c>`},
+		{"escaped title", `Tom &amp; Jerry &lt;3`},
+		{"sanitized description", `<c r="C2" t="inlineStr"><is><t xml:space="preserve">Hello</t></is></c>`},
+		{"date cell", `<c r="D2" s="2"><v>38719.627835648`},
+		{"unparseable date kept as text", `<c r="D3" t="inlineStr"><is><t xml:space="preserve">yesterday</t></is></c>`},
+		{"hyperlink cell", `<c r="B2" t="inlineStr" s="3">`},
+		{"hyperlink reference", `<hyperlink ref="B2" r:id="rId1"/>`},
+		{"column widths", `<col min="1" max="1" width="`},
+	}
+	for _, check := range checks {
+		if !strings.Contains(sheet, check.want) {
+			t.Errorf("sheet is missing %s: %s", check.desc, check.want)
+		}
+	}
+	if strings.Contains(sheet, `ref="B3"`) {
+		t.Error("non-URL link value should not become a hyperlink")
+	}
+
+	rels := parts["xl/worksheets/_rels/sheet1.xml.rels"]
+	if !strings.Contains(rels, `Target="https://example.com/1?a=1&amp;b=2" TargetMode="External"`) {
+		t.Errorf("hyperlink relationship missing or unescaped: %s", rels)
+	}
+}
+
+func TestXLSXExporter_Export_ChannelSummary(t *testing.T) {
+	rss := &models.RSS{
+		Channel: models.Channel{
+			Title: "Example Feed",
+			Items: []models.Item{{Title: "Item 1"}},
+		},
+	}
+
+	parts := readXLSX(t, rss, ExportOptions{Mode: ModeChannel})
+
+	if !strings.Contains(parts["xl/workbook.xml"], `<sheet name="Channel"`) {
+		t.Errorf("workbook sheet name should be Channel: %s", parts["xl/workbook.xml"])
+	}
+	if !strings.Contains(parts["xl/worksheets/sheet1.xml"], "Example Feed") {
+		t.Error("channel summary sheet is missing the feed title")
+	}
+	if _, ok := parts["xl/worksheets/_rels/sheet1.xml.rels"]; ok {
+		t.Error("sheet without hyperlinks should not have a relationships part")
+	}
+}
+
+func TestCellRef(t *testing.T) {
+	tests := []struct {
+		col, row int
+		want     string
+	}{
+		{0, 1, "A1"},
+		{25, 2, "Z2"},
+		{26, 3, "AA3"},
+		{27, 10, "AB10"},
+		{701, 1, "ZZ1"},
+		{702, 1, "AAA1"},
+	}
+
+	for _, tt := range tests {
+		if got := cellRef(tt.col, tt.row); got != tt.want {
+			t.Errorf("cellRef(%d, %d) = %q, want %q", tt.col, tt.row, got, tt.want)
+		}
+	}
+}
+
+func TestTruncateCell(t *testing.T) {
+	long := strings.Repeat("a", xlsxMaxCellLength+10)
+	if got := truncateCell(long); len(got) != xlsxMaxCellLength {
+		t.Errorf("len(truncateCell()) = %d, want %d", len(got), xlsxMaxCellLength)
+	}
+
+	// Astral characters take two UTF-16 code units each
+	emoji := strings.Repeat("😀", xlsxMaxCellLength)
+	if got := truncateCell(emoji); strings.Count(got, "😀") != xlsxMaxCellLength/2 {
+		t.Errorf("truncateCell() kept %d emoji, want %d", strings.Count(got, "😀"), xlsxMaxCellLength/2)
+	}
+}
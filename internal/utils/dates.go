@@ -0,0 +1,114 @@
+package utils
+
+import (
+	"strings"
+	"time"
+	"unicode"
+)
+
+// dateLayouts lists the publication date formats commonly found in feeds.
+// Dates are normalized before matching: leading weekday names are removed
+// and zone abbreviations are replaced by numeric offsets.
+var dateLayouts = []string{
+	// RFC 822 and RFC 1123 with 4 or 2 digit years, with or without seconds
+	"2 Jan 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04 -0700",
+	"2 Jan 06 15:04:05 -0700",
+	"2 Jan 06 15:04 -0700",
+	"2 January 2006 15:04:05 -0700",
+	"2 Jan 2006 15:04:05",
+	"2 Jan 2006",
+	// RFC 3339 and ISO 8601 variants
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999-0700",
+	"2006-01-02T15:04Z07:00",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02 15:04:05.999999999 -0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02",
+	"2006/01/02 15:04:05",
+	"2006/01/02",
+	// Unix date(1) and prose dates
+	"Jan 2 15:04:05 -0700 2006",
+	"Jan 2 15:04:05 2006",
+	"January 2, 2006 15:04:05",
+	"January 2, 2006",
+	"Jan 2, 2006",
+}
+
+// zoneOffsets maps the zone abbreviations allowed by RFC 822, and a few other
+// common ones, to numeric offsets. time.Parse only knows the offset of the
+// local zone's abbreviations and would silently treat the rest as UTC.
+var zoneOffsets = map[string]string{
+	"UT":   "+0000",
+	"UTC":  "+0000",
+	"GMT":  "+0000",
+	"Z":    "+0000",
+	"EST":  "-0500",
+	"EDT":  "-0400",
+	"CST":  "-0600",
+	"CDT":  "-0500",
+	"MST":  "-0700",
+	"MDT":  "-0600",
+	"PST":  "-0800",
+	"PDT":  "-0700",
+	"BST":  "+0100",
+	"CET":  "+0100",
+	"CEST": "+0200",
+	"EET":  "+0200",
+	"EEST": "+0300",
+	"JST":  "+0900",
+	"AEST": "+1000",
+	"AEDT": "+1100",
+}
+
+// ParseDate parses a feed date string in any of the common RSS, Atom and
+// JSON Feed formats. It reports false if the value could not be parsed.
+func ParseDate(value string) (time.Time, bool) {
+	value = normalizeDate(value)
+	if value == "" {
+		return time.Time{}, false
+	}
+	for _, layout := range dateLayouts {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// normalizeDate collapses whitespace, drops a leading weekday, which feeds
+// often misspell or write out in full, and replaces zone abbreviations with
+// numeric offsets
+func normalizeDate(value string) string {
+	fields := strings.Fields(value)
+	if len(fields) > 0 && isWeekday(fields[0]) {
+		fields = fields[1:]
+	}
+	for i, field := range fields {
+		if offset, ok := zoneOffsets[strings.ToUpper(field)]; ok && i > 0 {
+			fields[i] = offset
+		}
+	}
+	return strings.Join(fields, " ")
+}
+
+// isWeekday reports whether a field is a weekday name such as "Mon," or "Tuesday"
+func isWeekday(field string) bool {
+	name := strings.TrimSuffix(field, ",")
+	if len(name) < 3 {
+		return false
+	}
+	for _, r := range name {
+		if !unicode.IsLetter(r) {
+			return false
+		}
+	}
+	for _, day := range []string{"mon", "tue", "wed", "thu", "fri", "sat", "sun"} {
+		if strings.HasPrefix(strings.ToLower(name), day) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDate(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		want   time.Time
+		wantOK bool
+	}{
+		{
+			name:   "RFC 1123 with numeric zone",
+			input:  "Mon, 02 Jan 2006 15:04:05 -0700",
+			want:   time.Date(2006, 1, 2, 22, 4, 5, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "RFC 1123 with single digit day",
+			input:  "Tue, 3 Jan 2006 15:04:05 +0000",
+			want:   time.Date(2006, 1, 3, 15, 4, 5, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "RFC 3339",
+			input:  "2024-01-01T09:00:00Z",
+			want:   time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "date only",
+			input:  " 2024-03-15 ",
+			want:   time.Date(2024, 3, 15, 0, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "RFC 822 zone abbreviation",
+			input:  "Wed, 03 Jan 2024 08:30:00 PST",
+			want:   time.Date(2024, 1, 3, 16, 30, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "full weekday, no seconds and two digit year",
+			input:  "Wednesday,  3 Jan 24 08:30 GMT",
+			want:   time.Date(2024, 1, 3, 8, 30, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "wrong weekday",
+			input:  "Fri, 03 Jan 2024 08:30:00 +0100",
+			want:   time.Date(2024, 1, 3, 7, 30, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "ISO 8601 without colon in offset",
+			input:  "2024-01-03T08:30:00.123+0200",
+			want:   time.Date(2024, 1, 3, 6, 30, 0, 123000000, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "space separated with offset",
+			input:  "2024-01-03 08:30:00+02:00",
+			want:   time.Date(2024, 1, 3, 6, 30, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "Unix date",
+			input:  "Wed Jan  3 08:30:00 EST 2024",
+			want:   time.Date(2024, 1, 3, 13, 30, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "prose date",
+			input:  "January 3, 2024",
+			want:   time.Date(2024, 1, 3, 0, 0, 0, 0, time.UTC),
+			wantOK: true,
+		},
+		{
+			name:   "empty",
+			input:  "",
+			wantOK: false,
+		},
+		{
+			name:   "garbage",
+			input:  "sometime last week",
+			wantOK: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParseDate(tt.input)
+			if ok != tt.wantOK {
+				t.Fatalf("ParseDate(%q) ok = %v, want %v", tt.input, ok, tt.wantOK)
+			}
+			if ok && !got.Equal(tt.want) {
+				t.Errorf("ParseDate(%q) = %v, want %v", tt.input, got, tt.want)
+			}
+		})
+	}
+}
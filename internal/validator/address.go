@@ -0,0 +1,180 @@
+package validator
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"strings"
+	"syscall"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+// maxRedirects matches the net/http default redirect limit
+const maxRedirects = 10
+
+// AddressPolicy decides which network destinations the server may connect to
+// on behalf of a user. Loopback, private, link-local, multicast, unspecified
+// and other special-purpose addresses are always blocked, along with any
+// configured CIDRs, unless the address or host name is explicitly allowed.
+type AddressPolicy struct {
+	blocked      []netip.Prefix
+	allowed      []netip.Prefix
+	allowedHosts map[string]bool
+}
+
+// NewAddressPolicy creates an address policy. CIDR lists also accept single
+// IP addresses. Allowed CIDRs and host names take precedence over blocks.
+func NewAddressPolicy(blockedCIDRs, allowedCIDRs, allowedHosts []string) (*AddressPolicy, error) {
+	blocked, err := parsePrefixes(blockedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+	allowed, err := parsePrefixes(allowedCIDRs)
+	if err != nil {
+		return nil, err
+	}
+
+	hosts := make(map[string]bool, len(allowedHosts))
+	for _, host := range allowedHosts {
+		hosts[normalizeHost(host)] = true
+	}
+
+	return &AddressPolicy{
+		blocked:      blocked,
+		allowed:      allowed,
+		allowedHosts: hosts,
+	}, nil
+}
+
+// CheckAddr returns an error wrapping errors.ErrBlockedAddress if connecting
+// to addr is not permitted
+func (p *AddressPolicy) CheckAddr(addr netip.Addr) error {
+	addr = addr.Unmap()
+	if containsAddr(p.allowed, addr) {
+		return nil
+	}
+	if isReservedAddr(addr) || containsAddr(p.blocked, addr) {
+		return fmt.Errorf("%w: %s", errors.ErrBlockedAddress, addr)
+	}
+	return nil
+}
+
+// HostAllowed reports whether a host name is on the allowlist and so skips
+// address checks
+func (p *AddressPolicy) HostAllowed(host string) bool {
+	return p.allowedHosts[normalizeHost(host)]
+}
+
+// DialContext wraps a dialer so every resolved address is checked right
+// before the connection is made. Checking at dial time rather than when the
+// URL is validated means DNS answers cannot be swapped in between.
+func (p *AddressPolicy) DialContext(dialer *net.Dialer) func(ctx context.Context, network, address string) (net.Conn, error) {
+	guarded := *dialer
+	guarded.Control = func(network, address string, _ syscall.RawConn) error {
+		host, _, err := net.SplitHostPort(address)
+		if err != nil {
+			return err
+		}
+		addr, err := netip.ParseAddr(host)
+		if err != nil {
+			return fmt.Errorf("%w: unresolved address %s", errors.ErrBlockedAddress, address)
+		}
+		return p.CheckAddr(addr.WithZone(""))
+	}
+
+	return func(ctx context.Context, network, address string) (net.Conn, error) {
+		if host, _, err := net.SplitHostPort(address); err == nil && p.HostAllowed(host) {
+			return dialer.DialContext(ctx, network, address)
+		}
+		return guarded.DialContext(ctx, network, address)
+	}
+}
+
+// CheckRedirect is an http.Client redirect hook that re-validates every
+// redirect target. Host names are checked again when the redirect is dialed.
+func (p *AddressPolicy) CheckRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= maxRedirects {
+		return fmt.Errorf("stopped after %d redirects", maxRedirects)
+	}
+	if req.URL.Scheme != "http" && req.URL.Scheme != "https" {
+		return fmt.Errorf("%w: redirect to unsupported scheme %q", errors.ErrBlockedAddress, req.URL.Scheme)
+	}
+
+	host := req.URL.Hostname()
+	if p.HostAllowed(host) {
+		return nil
+	}
+	if addr, err := netip.ParseAddr(host); err == nil {
+		return p.CheckAddr(addr)
+	}
+	return nil
+}
+
+// reservedPrefixes are special-purpose networks the netip predicates do not
+// cover. Shared address space holds cloud metadata endpoints such as
+// 100.100.100.200, and NAT64 prefixes can reach any IPv4 address.
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),      // "this network"
+	netip.MustParsePrefix("100.64.0.0/10"),  // shared address space (carrier-grade NAT)
+	netip.MustParsePrefix("192.0.0.0/24"),   // IETF protocol assignments
+	netip.MustParsePrefix("198.18.0.0/15"),  // benchmarking
+	netip.MustParsePrefix("240.0.0.0/4"),    // reserved, including broadcast
+	netip.MustParsePrefix("64:ff9b::/96"),   // NAT64 well-known prefix
+	netip.MustParsePrefix("64:ff9b:1::/48"), // NAT64 local-use prefix
+}
+
+// isReservedAddr reports whether addr is never a legitimate public feed host
+func isReservedAddr(addr netip.Addr) bool {
+	return addr.IsLoopback() ||
+		addr.IsPrivate() ||
+		addr.IsLinkLocalUnicast() ||
+		addr.IsLinkLocalMulticast() ||
+		addr.IsInterfaceLocalMulticast() ||
+		addr.IsMulticast() ||
+		addr.IsUnspecified() ||
+		containsAddr(reservedPrefixes, addr)
+}
+
+// containsAddr reports whether any prefix contains addr
+func containsAddr(prefixes []netip.Prefix, addr netip.Addr) bool {
+	for _, prefix := range prefixes {
+		if prefix.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// parsePrefixes parses CIDRs, treating bare addresses as single-host prefixes
+func parsePrefixes(values []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(values))
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" {
+			continue
+		}
+		if !strings.Contains(value, "/") {
+			addr, err := netip.ParseAddr(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid IP address %q: %w", value, err)
+			}
+			addr = addr.Unmap()
+			prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+			continue
+		}
+		prefix, err := netip.ParsePrefix(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid CIDR %q: %w", value, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return prefixes, nil
+}
+
+// normalizeHost lower-cases a host name and drops any trailing dot
+func normalizeHost(host string) string {
+	return strings.TrimSuffix(strings.ToLower(strings.TrimSpace(host)), ".")
+}
@@ -0,0 +1,152 @@
+package validator
+
+import (
+	"context"
+	stderrors "errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+
+	"rss-feed-to-csv/internal/errors"
+)
+
+func TestAddressPolicy_CheckAddr(t *testing.T) {
+	policy, err := NewAddressPolicy(
+		[]string{"203.0.113.0/24", "198.51.100.7"},
+		[]string{"10.20.0.0/16"},
+		nil,
+	)
+	if err != nil {
+		t.Fatalf("NewAddressPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		addr    string
+		blocked bool
+	}{
+		{"93.184.216.34", false},
+		{"2606:2800:220:1::1", false},
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"::ffff:127.0.0.1", true},
+		{"10.0.0.1", true},
+		{"172.16.5.4", true},
+		{"192.168.1.1", true},
+		{"fd00::1", true},
+		{"169.254.169.254", true},
+		{"fe80::1", true},
+		{"224.0.0.1", true},
+		{"ff02::1", true},
+		{"0.0.0.0", true},
+		{"0.1.2.3", true},
+		{"100.64.0.1", true},
+		{"100.100.100.200", true},
+		{"100.128.0.1", false},
+		{"192.0.0.8", true},
+		{"198.18.0.1", true},
+		{"198.19.255.255", true},
+		{"198.20.0.1", false},
+		{"255.255.255.255", true},
+		{"64:ff9b::a9fe:a9fe", true},
+		{"64:ff9b:1::1", true},
+		{"203.0.113.9", true},
+		{"198.51.100.7", true},
+		{"198.51.100.8", false},
+		{"10.20.30.40", false},
+	}
+
+	for _, tt := range tests {
+		err := policy.CheckAddr(netip.MustParseAddr(tt.addr))
+		if (err != nil) != tt.blocked {
+			t.Errorf("CheckAddr(%s) error = %v, want blocked %v", tt.addr, err, tt.blocked)
+		}
+		if err != nil && !stderrors.Is(err, errors.ErrBlockedAddress) {
+			t.Errorf("CheckAddr(%s) error = %v, want ErrBlockedAddress", tt.addr, err)
+		}
+	}
+}
+
+func TestNewAddressPolicy_InvalidCIDR(t *testing.T) {
+	if _, err := NewAddressPolicy([]string{"10.0.0.0/33"}, nil, nil); err == nil {
+		t.Error("NewAddressPolicy() should reject an invalid blocked CIDR")
+	}
+	if _, err := NewAddressPolicy(nil, []string{"internal"}, nil); err == nil {
+		t.Error("NewAddressPolicy() should reject an invalid allowed address")
+	}
+}
+
+func TestAddressPolicy_DialContext(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	_, port, _ := net.SplitHostPort(server.Listener.Addr().String())
+
+	tests := []struct {
+		name         string
+		address      string
+		allowedCIDRs []string
+		allowedHosts []string
+		wantBlocked  bool
+	}{
+		{name: "loopback blocked", address: "127.0.0.1:" + port, wantBlocked: true},
+		{name: "host resolving to loopback blocked", address: "localhost:" + port, wantBlocked: true},
+		{name: "allowed CIDR", address: "127.0.0.1:" + port, allowedCIDRs: []string{"127.0.0.0/8"}},
+		{name: "allowed host", address: "localhost:" + port, allowedHosts: []string{"LOCALHOST."}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			policy, err := NewAddressPolicy(nil, tt.allowedCIDRs, tt.allowedHosts)
+			if err != nil {
+				t.Fatalf("NewAddressPolicy() error = %v", err)
+			}
+
+			conn, err := policy.DialContext(&net.Dialer{})(context.Background(), "tcp", tt.address)
+			if conn != nil {
+				conn.Close()
+			}
+			if tt.wantBlocked {
+				if !stderrors.Is(err, errors.ErrBlockedAddress) {
+					t.Errorf("DialContext() error = %v, want ErrBlockedAddress", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("DialContext() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestAddressPolicy_CheckRedirect(t *testing.T) {
+	policy, err := NewAddressPolicy(nil, nil, []string{"feeds.internal"})
+	if err != nil {
+		t.Fatalf("NewAddressPolicy() error = %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		via     int
+		wantErr bool
+	}{
+		{name: "public host", target: "https://example.com/feed"},
+		{name: "metadata address", target: "http://169.254.169.254/latest/meta-data/", wantErr: true},
+		{name: "loopback address", target: "http://[::1]:8080/", wantErr: true},
+		{name: "unsupported scheme", target: "file:///etc/passwd", wantErr: true},
+		{name: "allowed host", target: "http://feeds.internal/rss"},
+		{name: "too many redirects", target: "https://example.com/feed", via: 10, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			target, _ := url.Parse(tt.target)
+			err := policy.CheckRedirect(&http.Request{URL: target}, make([]*http.Request, tt.via))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("CheckRedirect() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}